@@ -0,0 +1,72 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/ta-ma/prop-filter-app/internal/render"
+)
+
+// configCmd groups the commands which inspect the configuration
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration",
+}
+
+// validateCmd checks the configuration without connecting to the database
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration without connecting to the database",
+	Long: `Checks the database settings, theme and key map of the configuration. Formulas are
+validated whenever the application starts. Exits with code 2 if the configuration is not valid.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceErrors, cmd.SilenceUsage = true, true
+
+		configuration := configFrom(cmd.Context())
+		err := errors.Join(configuration.DbConfig.Validate(), render.ValidateConfig(&configuration.Cli))
+		if err != nil {
+			fmt.Println("Invalid configuration:")
+			fmt.Println(err)
+			return &exitError{code: ExitConfigError, err: err}
+		}
+
+		fmt.Println("Configuration is valid.")
+		return nil
+	},
+}
+
+// showCmd prints the configuration resulting from every source
+var showCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration with secrets redacted",
+	Long: `Prints the configuration built from the defaults, the configuration file, the PROPFILTER_*
+environment variables, DATABASE_URL and the command-line flags, in that order of precedence.
+The database password is not displayed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath := configPathFrom(cmd.Context())
+		if configPath == "" {
+			configPath = "none"
+		}
+
+		out, err := json.MarshalIndent(configFrom(cmd.Context()).Redacted(), "", "  ")
+		if err != nil {
+			fmt.Println("Failed to display configuration:", err)
+			return err
+		}
+
+		fmt.Println("Configuration file:", configPath)
+		fmt.Println(string(out))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(validateCmd)
+	configCmd.AddCommand(showCmd)
+}
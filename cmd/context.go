@@ -0,0 +1,47 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package cmd
+
+import (
+	"context"
+
+	"github.com/ta-ma/prop-filter-app/internal/config"
+	"github.com/ta-ma/prop-filter-app/internal/db"
+)
+
+type contextKey int
+
+const (
+	configKey contextKey = iota
+	configPathKey
+	repositoryKey
+)
+
+// withConfig returns a copy of the context holding the configuration and the path of the file
+// it was read from, empty if there was none.
+func withConfig(ctx context.Context, configuration *config.Configuration, path string) context.Context {
+	ctx = context.WithValue(ctx, configKey, configuration)
+	return context.WithValue(ctx, configPathKey, path)
+}
+
+// configFrom returns the configuration held by the context of a command.
+func configFrom(ctx context.Context) *config.Configuration {
+	return ctx.Value(configKey).(*config.Configuration)
+}
+
+// configPathFrom returns the path of the configuration file held by the context of a command.
+func configPathFrom(ctx context.Context) string {
+	return ctx.Value(configPathKey).(string)
+}
+
+// withRepository returns a copy of the context holding the database repository.
+func withRepository(ctx context.Context, repo *db.Repository) context.Context {
+	return context.WithValue(ctx, repositoryKey, repo)
+}
+
+// repositoryFrom returns the database repository held by the context of a command, which is
+// only set for commands with the needsDatabase annotation.
+func repositoryFrom(ctx context.Context) *db.Repository {
+	return ctx.Value(repositoryKey).(*db.Repository)
+}
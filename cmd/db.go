@@ -0,0 +1,134 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/ta-ma/prop-filter-app/internal/db"
+	"github.com/ta-ma/prop-filter-app/internal/querylog"
+)
+
+// dbCmd groups the commands which maintain the database
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Maintain the properties database",
+}
+
+// analyzeCmd suggests indexes from the queries which have been run
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Suggest indexes for the queries recorded in the query log",
+	Long: `Reads the query log and suggests composite indexes for the fields which are
+often filtered together, along with the default indexes which are missing.
+Use --create to create them.`,
+	Annotations: map[string]string{needsDatabaseAnnotation: ""},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		repo := repositoryFrom(ctx)
+		logPath, _ := cmd.Flags().GetString("log")
+		minQueries, _ := cmd.Flags().GetInt("min-queries")
+		create, _ := cmd.Flags().GetBool("create")
+
+		if logPath == "" {
+			logPath = configFrom(ctx).Cli.QueryLog
+		}
+		if logPath == "" {
+			fmt.Println("ERROR: No query log has been configured, set QueryLog in the configuration or use --log.")
+			return
+		}
+
+		entries, err := querylog.Read(logPath)
+		if err != nil {
+			fmt.Println("Failed to read query log:", err)
+			return
+		}
+
+		var missing []db.Index
+		for _, index := range db.DefaultIndexes {
+			if !repo.HasIndex(ctx, index) {
+				missing = append(missing, index)
+			}
+		}
+
+		type suggestion struct {
+			index   db.Index
+			queries int
+		}
+		var suggestions []suggestion
+		for _, s := range querylog.Suggest(entries, db.IndexedFields, minQueries) {
+			index := db.ListingsIndex(s.Columns)
+			if !repo.HasIndex(ctx, index) {
+				suggestions = append(suggestions, suggestion{index: index, queries: s.Queries})
+			}
+		}
+
+		fmt.Printf("Analyzed %d queries from %s.\n", len(entries), logPath)
+		if len(missing) == 0 && len(suggestions) == 0 {
+			fmt.Println("No indexes to suggest.")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "\nIndex\tColumns\tReason")
+		for _, index := range missing {
+			fmt.Fprintf(w, "%s\t%s (%s)\tdefault index\n", index.Name(), index.Table, strings.Join(index.Columns, ", "))
+		}
+		for _, s := range suggestions {
+			fmt.Fprintf(w, "%s\t%s (%s)\tfiltered together by %d queries\n", s.index.Name(), s.index.Table,
+				strings.Join(s.index.Columns, ", "), s.queries)
+		}
+		w.Flush()
+
+		if !create {
+			fmt.Println("\nRun again with --create to create them.")
+			return
+		}
+
+		fmt.Println()
+		indexes := missing
+		for _, s := range suggestions {
+			indexes = append(indexes, s.index)
+		}
+		for _, index := range indexes {
+			if err := repo.CreateIndex(ctx, index); err != nil {
+				fmt.Println("Failed to create index:", err)
+				return
+			}
+			fmt.Println("Created index", index.Name())
+		}
+	},
+}
+
+// refreshCmd updates the listings view after the properties have changed
+var refreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Refresh the view properties are listed from",
+	Long: `Properties are listed from a materialized view, which is created when seeding the
+database. Use this command after importing or updating properties so they are listed
+with their current values. The view is created if it doesn't exist.`,
+	Annotations: map[string]string{needsDatabaseAnnotation: ""},
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := repositoryFrom(cmd.Context()).RefreshListingsView(cmd.Context()); err != nil {
+			fmt.Println("Failed to refresh listings view:", err)
+			return
+		}
+
+		fmt.Println("Listings view refreshed.")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dbCmd)
+	dbCmd.AddCommand(analyzeCmd)
+	dbCmd.AddCommand(refreshCmd)
+
+	analyzeCmd.Flags().String("log", "", "Path of the query log, by default the QueryLog of the configuration")
+	analyzeCmd.Flags().Int("min-queries", 3, "Minimum amount of queries filtering by the same columns to suggest an index")
+	analyzeCmd.Flags().Bool("create", false, "Create the suggested indexes")
+}
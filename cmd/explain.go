@@ -0,0 +1,37 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ta-ma/prop-filter-app/internal/db"
+	"github.com/ta-ma/prop-filter-app/internal/filter"
+)
+
+// printExplanation prints how the filter parameters were parsed and the queries which list the
+// properties, along with their timings.
+func printExplanation(tree []filter.FilterNode, explanation db.QueryExplanation, pageNumber int) {
+	fmt.Println("Filter:")
+	if len(tree) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, node := range tree {
+		fmt.Printf("  %s \"%s\"\n", node.Field, node.Expr)
+		for _, part := range node.Parts {
+			fmt.Printf("    %s %s\n      -> %s\n", part.Operator, part.Value, part.Sql)
+		}
+	}
+
+	fmt.Printf("\nCount query (%d properties, %s):\n  %s\n", explanation.Count, explanation.CountDuration, explanation.CountSql)
+	fmt.Printf("\nPage query (page %d, %d properties, %s):\n  %s\n", pageNumber, explanation.Rows, explanation.PageDuration,
+		explanation.PageSql)
+
+	if len(explanation.Plan) > 0 {
+		fmt.Println("\nQuery plan:")
+		for _, line := range explanation.Plan {
+			fmt.Println("  " + line)
+		}
+	}
+}
@@ -4,15 +4,21 @@ Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/eiannone/keyboard"
 	"github.com/spf13/cobra"
+	"github.com/ta-ma/prop-filter-app/internal/columns"
 	"github.com/ta-ma/prop-filter-app/internal/db"
 	"github.com/ta-ma/prop-filter-app/internal/filter"
 	"github.com/ta-ma/prop-filter-app/internal/models"
+	"github.com/ta-ma/prop-filter-app/internal/querylog"
 	"github.com/ta-ma/prop-filter-app/internal/render"
 )
 
@@ -24,7 +30,10 @@ The resulting filtered data will be printed in a table, limited by the max amoun
 per page.
 
 Example: prop-filter-app query -w 10 -n 2 -p "<700000"`,
+	Annotations: map[string]string{needsDatabaseAnnotation: ""},
 	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		cfg, repo := &configFrom(ctx).Cli, repositoryFrom(ctx)
 		pageHeight, _ := cmd.Flags().GetInt("page-size")
 		pageNumber, _ := cmd.Flags().GetInt("page")
 		priceExpr, _ := cmd.Flags().GetString("price")
@@ -37,18 +46,24 @@ Example: prop-filter-app query -w 10 -n 2 -p "<700000"`,
 		amenitiesExpr, _ := cmd.Flags().GetString("amenities")
 		lightingExpr, _ := cmd.Flags().GetString("lighting")
 		distanceExpr, _ := cmd.Flags().GetString("distance")
+		columnsList, _ := cmd.Flags().GetString("columns")
+		whereExpr, _ := cmd.Flags().GetString("where")
+		sortValue, _ := cmd.Flags().GetString("sort")
+		explain, _ := cmd.Flags().GetBool("explain")
+		analyze, _ := cmd.Flags().GetBool("analyze")
 
 		translator := filter.Translator{}
 		translator.Init()
-		translator.Translate("p.price", priceExpr, filter.Num)
-		translator.Translate("p.rooms", roomsExpr, filter.Num)
-		translator.Translate("p.bathrooms", bathroomsExpr, filter.Num)
-		translator.Translate("p.latitude", latitudeExpr, filter.Num)
-		translator.Translate("p.longitude", longitudeExpr, filter.Num)
-		translator.Translate("p.square_footage", sqftExpr, filter.Num)
-		translator.Translate("p.description", descExpr, filter.Str)
-		translator.Translate("l.description", lightingExpr, filter.Lighting)
-		translator.Translate("a.amenities", amenitiesExpr, filter.Amenity)
+		translator.FullTextSearch = repo.HasFullTextSearch()
+		translate(&translator, "price", "p.price", priceExpr, filter.Num)
+		translate(&translator, "rooms", "p.rooms", roomsExpr, filter.Num)
+		translate(&translator, "bathrooms", "p.bathrooms", bathroomsExpr, filter.Num)
+		translate(&translator, "latitude", "p.latitude", latitudeExpr, filter.Num)
+		translate(&translator, "longitude", "p.longitude", longitudeExpr, filter.Num)
+		translate(&translator, "sqft", "p.square_footage", sqftExpr, filter.Num)
+		translate(&translator, "description", "p.description", descExpr, filter.FullText)
+		translate(&translator, "lighting", "p.lighting", lightingExpr, filter.Lighting)
+		translate(&translator, "amenities", "p.amenity_list", amenitiesExpr, filter.Amenity)
 
 		var calcDistance bool
 		var distanceData filter.DistanceFilterData
@@ -57,13 +72,63 @@ Example: prop-filter-app query -w 10 -n 2 -p "<700000"`,
 			distanceData = translator.TranslateDistanceExpr("d.dist", distanceExpr)
 		}
 
+		conditions, err := columns.ParseWhere(whereExpr, calcDistance)
+		if err != nil {
+			fmt.Println("Failed to parse where parameter:", err)
+			return
+		}
+		for _, c := range conditions {
+			if translator.Err != nil {
+				break
+			}
+			translator.Translate(c.Column.Sql, c.Expr, filter.Num)
+			if translator.Err != nil {
+				fmt.Println("Failed to parse where parameter:", c.WhereError(whereExpr, translator.Err))
+				return
+			}
+		}
+
 		if translator.Err != nil {
 			fmt.Println("Failed to parse filter parameters:", translator.Err)
 			return
 		}
 		sqlFilter := translator.GetSqlTranslation()
+		sqlOrder := translator.GetRankOrder()
+		similarity := translator.GetSimilarityScore()
 
-		propsCount, err := db.GetPropertiesCount(sqlFilter, calcDistance, distanceData.X, distanceData.Y)
+		cols, err := columns.Parse(columnsList, calcDistance, similarity != "")
+		if err != nil {
+			fmt.Println("Failed to parse columns parameter:", err)
+			return
+		}
+
+		sort, err := columns.ParseSort(sortValue, calcDistance, similarity != "")
+		if err != nil {
+			fmt.Println("Failed to parse sort parameter:", err)
+			return
+		}
+		order := sqlOrder
+		if sort != nil {
+			order = sort.Column.OrderBy(sort.Desc)
+		}
+
+		if cfg.QueryLog != "" {
+			if err := querylog.Append(cfg.QueryLog, querylog.NewEntry(translator.Tree, order)); err != nil {
+				fmt.Println("Query could not be logged:", err)
+			}
+		}
+
+		if explain || analyze {
+			explanation, err := repo.ExplainQuery(ctx, sqlFilter, order, pageHeight, (pageNumber-1)*pageHeight, calcDistance,
+				distanceData.X, distanceData.Y, similarity, analyze)
+			printExplanation(translator.Tree, explanation, pageNumber)
+			if err != nil {
+				fmt.Println("\nQuery failed:", err)
+			}
+			return
+		}
+
+		propsCount, err := repo.GetPropertiesCount(ctx, sqlFilter, calcDistance, distanceData.X, distanceData.Y)
 		if err != nil {
 			fmt.Println("Properties could not be counted:", err)
 			return
@@ -90,9 +155,11 @@ Example: prop-filter-app query -w 10 -n 2 -p "<700000"`,
 		}
 
 		if cfg.UseOldRender {
-			startLoop(pageNumber, pageHeight, maxPage, sqlFilter, calcDistance, distanceData.X, distanceData.Y)
+			startLoop(ctx, repo, pageNumber, pageHeight, maxPage, cols, cfg.TrimLength, sqlFilter, order, calcDistance, distanceData.X, distanceData.Y, similarity)
 		} else {
-			render.ShowTeaTable(pageNumber, pageHeight, maxPage, sqlFilter, calcDistance, distanceData.X, distanceData.Y)
+			autoHeight := !cmd.Flags().Changed("page-size")
+			render.ShowTeaTable(ctx, repo, pageNumber, pageHeight, autoHeight, propsCount, cols, getFilterArgs(cmd), sqlFilter, sqlOrder, sort, calcDistance,
+				distanceData.X, distanceData.Y, similarity, cfg)
 		}
 	},
 }
@@ -112,32 +179,68 @@ func init() {
 	queryCmd.Flags().StringP("amenities", "a", "", "Expression to filter entries by the Amenities field")
 	queryCmd.Flags().StringP("lighting", "l", "", "Expression to filter entries by the Lighting field")
 	queryCmd.Flags().StringP("distance", "k", "", "Expression to filter entries by the Description field")
+	queryCmd.Flags().StringP("sort", "o", "", `Column to sort by, such as "price", adding ":desc" to sort in descending order`)
+	queryCmd.Flags().String("where", "", `Numerical conditions over any column separated by ";", such as "ppsf < 300"`)
+	queryCmd.Flags().Bool("explain", false, "Print the parsed filter, the SQL queries and their timings instead of the table")
+	queryCmd.Flags().Bool("analyze", false, "Same as --explain, also printing the query plan from EXPLAIN ANALYZE")
+	queryCmd.Flags().StringP("columns", "c", "", "Comma separated list of the columns to display, in order. Available columns: "+
+		strings.Join(columns.Keys(), ", "))
 }
 
-func printTable(result []models.PropertyViewModel, calcDist bool) {
+// translate translates the expression of a filter flag, naming the flag in its error.
+func translate(translator *filter.Translator, flag string, field string, expr string, exprType filter.ExprType) {
+	if translator.Err != nil {
+		return
+	}
+
+	translator.Translate(field, expr, exprType)
+	var exprErr *filter.ExprError
+	if errors.As(translator.Err, &exprErr) {
+		exprErr.Source = "--" + flag
+	}
+}
+
+// getFilterArgs returns the filter flags that have been set and their values, in pairs.
+func getFilterArgs(cmd *cobra.Command) []string {
+	var args []string
+	for _, name := range []string{
+		"price", "rooms", "bathrooms", "latitude", "longitude", "sqft", "description", "amenities", "lighting", "distance", "where",
+	} {
+		if cmd.Flags().Changed(name) {
+			value, _ := cmd.Flags().GetString(name)
+			args = append(args, "--"+name, value)
+		}
+	}
+
+	return args
+}
+
+func printTable(result []models.PropertyViewModel, cols []columns.Column, trimLength int) {
 	tw := tabwriter.NewWriter(os.Stdout, 1, 1, 2, ' ', 0)
-	if calcDist {
-		fmt.Fprintf(tw, "Description\tPrice\tSquare ft\tRooms\tBathrooms\tLighting\tLocation\tDistance\tAmenities\n")
-		fmt.Fprintf(tw, "-----\t-----\t-----\t-----\t-----\t-----\t-----\t-----\t-----\t\n")
-		for _, r := range result {
-			fmt.Fprintf(tw, "%s\t%.2f\t%.2f\t%d\t%d\t%s\t(%.2f, %.2f)\t%.2f\t%s\n", trimString(r.Description),
-				r.Price, r.Square_footage, r.Rooms, r.Bathrooms, r.Lighting, r.Latitude, r.Longitude,
-				r.Dist, r.Amenities)
-		}
-	} else {
-		fmt.Fprintf(tw, "Description\tPrice\tSquare ft\tRooms\tBathrooms\tLighting\tLocation\tAmenities\n")
-		fmt.Fprintf(tw, "-----\t-----\t-----\t-----\t-----\t-----\t-----\t-----\t\n")
-		for _, r := range result {
-			fmt.Fprintf(tw, "%s\t%.2f\t%.2f\t%d\t%d\t%s\t(%.2f, %.2f)\t%s\n", trimString(r.Description),
-				r.Price, r.Square_footage, r.Rooms, r.Bathrooms, r.Lighting, r.Latitude,
-				r.Longitude, r.Amenities)
+
+	titles := make([]string, len(cols))
+	separators := make([]string, len(cols))
+	for i, c := range cols {
+		titles[i] = c.Title
+		separators[i] = "-----"
+	}
+	fmt.Fprintf(tw, "%s\n", strings.Join(titles, "\t"))
+	fmt.Fprintf(tw, "%s\t\n", strings.Join(separators, "\t"))
+
+	for _, r := range result {
+		row := columns.Row(r, cols)
+		for i, c := range cols {
+			if c.Key == "description" {
+				row[i] = trimString(row[i], trimLength)
+			}
 		}
+		fmt.Fprintf(tw, "%s\n", strings.Join(row, "\t"))
 	}
 
 	tw.Flush()
 }
 
-func startLoop(startPageNumber int, pageHeight int, maxPage int, queryFilter string, calcDistance bool, distX string, distY string) {
+func startLoop(ctx context.Context, repo *db.Repository, startPageNumber int, pageHeight int, maxPage int, cols []columns.Column, trimLength int, queryFilter string, queryOrder string, calcDistance bool, distX string, distY string, similarity string) {
 	pageNumber := startPageNumber
 	invalidKeyPressed := false
 
@@ -150,14 +253,14 @@ func startLoop(startPageNumber int, pageHeight int, maxPage int, queryFilter str
 
 	for {
 		if !invalidKeyPressed {
-			properties, err := db.QueryProperties(queryFilter, pageHeight, (pageNumber-1)*pageHeight, calcDistance, distX, distY)
+			properties, err := repo.QueryProperties(ctx, queryFilter, queryOrder, pageHeight, (pageNumber-1)*pageHeight, calcDistance, distX, distY, similarity)
 			if err != nil {
 				fmt.Println("Properties could not be queried:", err)
 				return
 			}
 
 			fmt.Println()
-			printTable(properties, calcDistance)
+			printTable(properties, cols, trimLength)
 			fmt.Println()
 			fmt.Printf("Page %d / %d\n", pageNumber, maxPage)
 			if pageNumber != 1 {
@@ -166,12 +269,13 @@ func startLoop(startPageNumber int, pageHeight int, maxPage int, queryFilter str
 			if pageNumber != maxPage {
 				fmt.Print("RightArrow -> next page  ")
 			}
-			fmt.Print("ESC and ENTER -> exit\n")
+			fmt.Print("PgUp/PgDn -> skip 10 pages  Home/End or g/G -> first/last page  " +
+				": -> go to page  # -> go to property ID  ESC and ENTER -> exit\n")
 		} else {
 			invalidKeyPressed = false
 		}
 
-		_, key, err := keyboard.GetKey()
+		char, key, err := keyboard.GetKey()
 
 		if err != nil {
 			panic(err)
@@ -183,6 +287,36 @@ func startLoop(startPageNumber int, pageHeight int, maxPage int, queryFilter str
 			pageNumber--
 		} else if key == keyboard.KeyArrowRight && pageNumber != maxPage {
 			pageNumber++
+		} else if key == keyboard.KeyPgup && pageNumber != 1 {
+			pageNumber = max(pageNumber-10, 1)
+		} else if key == keyboard.KeyPgdn && pageNumber != maxPage {
+			pageNumber = min(pageNumber+10, maxPage)
+		} else if (key == keyboard.KeyHome || char == 'g') && pageNumber != 1 {
+			pageNumber = 1
+		} else if (key == keyboard.KeyEnd || char == 'G') && pageNumber != maxPage {
+			pageNumber = maxPage
+		} else if char == ':' {
+			page, ok := readNumber("Go to page: ")
+			if !ok || page < 1 || page > maxPage {
+				invalidKeyPressed = true
+				fmt.Printf("Page must be between 1 and %d\n", maxPage)
+				continue
+			}
+			pageNumber = page
+		} else if char == '#' {
+			id, ok := readNumber("Go to property ID: ")
+			if !ok {
+				invalidKeyPressed = true
+				fmt.Println("Invalid property ID")
+				continue
+			}
+			position, err := repo.GetPropertyPosition(ctx, uint(id), queryFilter, queryOrder, calcDistance, distX, distY, similarity)
+			if err != nil {
+				invalidKeyPressed = true
+				fmt.Println("Could not go to property:", err)
+				continue
+			}
+			pageNumber = (position-1)/pageHeight + 1
 		} else {
 			invalidKeyPressed = true
 			fmt.Println("Invalid key pressed")
@@ -190,9 +324,38 @@ func startLoop(startPageNumber int, pageHeight int, maxPage int, queryFilter str
 	}
 }
 
-func trimString(str string) string {
-	if cfg.TrimLength != 0 && len(str) > cfg.TrimLength {
-		return str[0:cfg.TrimLength-3] + "..."
+// readNumber prints the prompt and reads digits until ENTER is pressed. Returns false if
+// nothing was entered or the input was cancelled with ESC.
+func readNumber(prompt string) (int, bool) {
+	var digits []rune
+	fmt.Print(prompt)
+	defer fmt.Println()
+
+	for {
+		char, key, err := keyboard.GetKey()
+		if err != nil {
+			panic(err)
+		}
+
+		switch {
+		case key == keyboard.KeyEsc:
+			return 0, false
+		case key == keyboard.KeyEnter:
+			number, err := strconv.Atoi(string(digits))
+			return number, err == nil
+		case (key == keyboard.KeyBackspace || key == keyboard.KeyBackspace2) && len(digits) > 0:
+			digits = digits[:len(digits)-1]
+			fmt.Print("\b \b")
+		case char >= '0' && char <= '9' && len(digits) < 10:
+			digits = append(digits, char)
+			fmt.Print(string(char))
+		}
+	}
+}
+
+func trimString(str string, trimLength int) string {
+	if trimLength != 0 && len(str) > trimLength {
+		return str[0:trimLength-3] + "..."
 	}
 
 	return str
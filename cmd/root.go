@@ -4,13 +4,38 @@ Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/ta-ma/prop-filter-app/internal/columns"
 	"github.com/ta-ma/prop-filter-app/internal/config"
+	"github.com/ta-ma/prop-filter-app/internal/db"
+	"github.com/ta-ma/prop-filter-app/internal/render"
 )
 
-var cfg *config.Cli
+// Exit codes of the application, besides 0 on success
+const (
+	ExitFailure         = 1
+	ExitConfigError     = 2
+	ExitConnectionError = 3
+	ExitSeedError       = 4
+)
+
+// Commands with this annotation connect to the database before they run
+const needsDatabaseAnnotation = "needsDatabase"
+
+// exitError is an error which ends the application with a specific exit code. Its message
+// has already been printed.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -21,24 +46,111 @@ of operators. Use the command query -h for more information.`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Errors are printed here, so cobra shouldn't print them along with the usage
+		cmd.SilenceErrors, cmd.SilenceUsage = true, true
+
+		configPath, _ := cmd.Flags().GetString("config")
+		configuration, configPath, err := config.Load(configPath, os.LookupEnv)
+		if err != nil {
+			fmt.Println("ERROR: Invalid configuration:", err)
+			return &exitError{code: ExitConfigError, err: err}
+		}
+		applyFlags(cmd, &configuration)
+
+		if err := columns.RegisterFormulas(configuration.Cli.Formulas); err != nil {
+			fmt.Println("Invalid formulas configuration:", err)
+			return &exitError{code: ExitConfigError, err: err}
+		}
+		cmd.SetContext(withConfig(cmd.Context(), &configuration, configPath))
+
+		if _, ok := cmd.Annotations[needsDatabaseAnnotation]; !ok {
+			return nil
+		}
+
+		repo, err := openDatabase(cmd.Context(), &configuration.DbConfig)
+		if err != nil {
+			return err
+		}
+
+		cmd.SetContext(withRepository(cmd.Context(), repo))
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
-func Execute(config *config.Cli) {
-	cfg = config
-	err := rootCmd.Execute()
+func Execute(ctx context.Context) {
+	err := rootCmd.ExecuteContext(ctx)
 
+	var exit *exitError
+	if errors.As(err, &exit) {
+		os.Exit(exit.code)
+	}
 	if err != nil {
-		os.Exit(1)
+		os.Exit(ExitFailure)
 	}
 }
 
-func init() {
-	// Here you will define your flags and configuration settings.
-	// Cobra supports persistent flags, which, if defined here,
-	// will be global for your application.
+// openDatabase connects to the database and seeds it if it is configured to.
+func openDatabase(ctx context.Context, dbConfig *config.DbConfig) (*db.Repository, error) {
+	repo, err := db.Open(ctx, dbConfig)
+	if err != nil {
+		fmt.Println("ERROR:", err)
+		return nil, &exitError{code: ExitConnectionError, err: err}
+	}
+
+	if dbConfig.SeedDatabase {
+		if err := repo.SeedDatabase(ctx, dbConfig.SeedEntries); err != nil {
+			fmt.Println("ERROR: Seeding failed:", err)
+			return nil, &exitError{code: ExitSeedError, err: err}
+		}
+	}
+
+	return repo, nil
+}
+
+// applyFlags overrides the configuration with the settings given in the command line, which
+// take precedence over every other source.
+func applyFlags(cmd *cobra.Command, configuration *config.Configuration) {
+	flags := cmd.Flags()
+	dbConfig, cli := &configuration.DbConfig, &configuration.Cli
 
-	// Cobra also supports local flags, which will only run
-	// when this action is called directly.
+	if flags.Changed("db-host") {
+		dbConfig.Host, _ = flags.GetString("db-host")
+	}
+	if flags.Changed("db-port") {
+		dbConfig.Port, _ = flags.GetUint("db-port")
+	}
+	if flags.Changed("db-user") {
+		dbConfig.PgUser, _ = flags.GetString("db-user")
+	}
+	if flags.Changed("db-name") {
+		dbConfig.DbName, _ = flags.GetString("db-name")
+	}
+	if flags.Changed("seed") {
+		dbConfig.SeedDatabase, _ = flags.GetBool("seed")
+	}
+	if flags.Changed("seed-entries") {
+		dbConfig.SeedEntries, _ = flags.GetUint("seed-entries")
+	}
+	if flags.Changed("theme") {
+		cli.Theme, _ = flags.GetString("theme")
+	}
+	if flags.Changed("query-log") {
+		cli.QueryLog, _ = flags.GetString("query-log")
+	}
+}
+
+func init() {
+	flags := rootCmd.PersistentFlags()
+	flags.String("config", "", "Path of the JSON configuration file, by default ./config.json or the one in the user configuration directory")
+	flags.String("db-host", "", "Host of the Postgres server")
+	flags.Uint("db-port", 0, "Port of the Postgres server")
+	flags.String("db-user", "", "User connecting to Postgres")
+	flags.String("db-name", "", "Name of the database")
+	flags.Bool("seed", false, "Seed the database with random properties before running the command")
+	flags.Uint("seed-entries", 0, "Amount of properties created when seeding")
+	flags.String("theme", "", "Theme of the table: "+strings.Join(render.ThemeNames(), ", "))
+	flags.String("query-log", "", "File where the fields filtered by each query are recorded")
 }
@@ -0,0 +1,59 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package columns
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ta-ma/prop-filter-app/internal/models"
+)
+
+// computed are the columns derived from other fields of the properties. Their values are
+// calculated by the database when filtering and sorting, and by Value when displaying them.
+var computed = []Column{
+	{Key: "ppsf", Title: "Price/sqft", Width: 10, MinWidth: 8, Priority: 2, Sql: "(p.price / nullif(p.square_footage, 0))",
+		Format: formatPricePerSquareFoot, Value: pricePerSquareFoot, Best: Lowest, Optional: true},
+	{Key: "rooms_per_bathroom", Title: "Rooms/bath", Width: 10, MinWidth: 6, Priority: 3,
+		Sql:    "(p.rooms::float / nullif(p.bathrooms, 0))",
+		Format: formatRoomsPerBathroom, Value: roomsPerBathroom, Optional: true},
+	{Key: "amenity_count", Title: "Amenity count", Width: 8, MinWidth: 5, Priority: 5,
+		Sql:    "p.amenity_count",
+		Format: func(r models.PropertyViewModel) string { return fmt.Sprintf("%.0f", amenitiesCount(r)) },
+		Value:  amenitiesCount, Best: Highest, Optional: true},
+}
+
+// pricePerSquareFoot returns NaN for properties without square footage, like the NULL of the database.
+func pricePerSquareFoot(r models.PropertyViewModel) float64 {
+	if r.Square_footage == 0 {
+		return math.NaN()
+	}
+
+	return float64(r.Price / r.Square_footage)
+}
+
+func formatPricePerSquareFoot(r models.PropertyViewModel) string {
+	if v := pricePerSquareFoot(r); !math.IsNaN(v) {
+		return fmt.Sprintf("$%.2f", v)
+	}
+
+	return "-"
+}
+
+// roomsPerBathroom returns NaN for properties without bathrooms, like the NULL of the database.
+func roomsPerBathroom(r models.PropertyViewModel) float64 {
+	if r.Bathrooms == 0 {
+		return math.NaN()
+	}
+
+	return float64(r.Rooms) / float64(r.Bathrooms)
+}
+
+func formatRoomsPerBathroom(r models.PropertyViewModel) string {
+	if v := roomsPerBathroom(r); !math.IsNaN(v) {
+		return fmt.Sprintf("%.2f", v)
+	}
+
+	return "-"
+}
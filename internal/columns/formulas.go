@@ -0,0 +1,76 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package columns
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+
+	"github.com/ta-ma/prop-filter-app/internal/filter"
+	"github.com/ta-ma/prop-filter-app/internal/models"
+)
+
+var formulaNameRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// Fields which can be used in formulas besides the numerical columns
+var formulaFields = map[string]Column{
+	"latitude":  latitudeField,
+	"longitude": longitudeField,
+}
+
+// RegisterFormulas adds a column for each formula, such as "price * 0.0055 + 150", mapped by
+// the key of the column. Formulas can use numbers, the numerical columns and the latitude
+// and longitude fields.
+func RegisterFormulas(formulas map[string]string) error {
+	fields := make(map[string]Column)
+	for key, c := range formulaFields {
+		fields[key] = c
+	}
+	for _, c := range registry {
+		if c.numerical() && !c.NeedsDistance {
+			fields[c.Key] = c
+		}
+	}
+
+	fieldsSql := make(map[string]string)
+	for key, c := range fields {
+		fieldsSql[key] = c.Sql
+	}
+
+	keys := make([]string, 0, len(formulas))
+	for key := range formulas {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !formulaNameRegex.MatchString(key) {
+			return fmt.Errorf(`formula name "%s" must only have lowercase letters, numbers and "_"`, key)
+		}
+		if _, ok := find(key); ok {
+			return fmt.Errorf(`formula name "%s" is already used by a column`, key)
+		}
+
+		formula, err := filter.ParseFormula(formulas[key], fieldsSql)
+		if err != nil {
+			return err
+		}
+
+		value := func(r models.PropertyViewModel) float64 {
+			return formula.Eval(func(field string) float64 { return fields[field].Value(r) })
+		}
+		registry = append(registry, Column{Key: key, Title: key, Width: 10, MinWidth: 6, Priority: 5,
+			Sql: formula.Sql(), Value: value, Optional: true,
+			Format: func(r models.PropertyViewModel) string {
+				if v := value(r); !math.IsNaN(v) {
+					return fmt.Sprintf("%.2f", v)
+				}
+				return "-"
+			}})
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package columns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ta-ma/prop-filter-app/internal/models"
+)
+
+func TestRegisterFormulas(t *testing.T) {
+	type testCase struct {
+		formulas    map[string]string
+		errExpected bool
+	}
+
+	testCases := []testCase{
+		{formulas: map[string]string{"price": "price * 2"}, errExpected: true},
+		{formulas: map[string]string{"Monthly Cost": "price"}, errExpected: true},
+		{formulas: map[string]string{"bad": "price * lighting"}, errExpected: true},
+		{formulas: map[string]string{"bad": "distance * 2"}, errExpected: true},
+	}
+
+	original := registry
+	defer func() { registry = original }()
+
+	for _, test := range testCases {
+		registry = original
+		err := RegisterFormulas(test.formulas)
+
+		if test.errExpected {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+	}
+
+	registry = original
+	err := RegisterFormulas(map[string]string{"monthly_cost": "price * 0.0055 + 150", "cost_per_room": "price / rooms"})
+	assert.NoError(t, err)
+
+	cols, err := Parse("monthly_cost,cost_per_room", false, false)
+	assert.NoError(t, err)
+	property := models.PropertyViewModel{Price: 200000, Rooms: 0}
+	assert.Equal(t, []string{"1250.00", "-"}, Row(property, cols))
+	assert.Equal(t, "((p.price * 0.0055) + 150)", cols[0].Sql)
+	assert.Equal(t, "(p.price::float / nullif(p.rooms, 0))", cols[1].Sql)
+	assert.Equal(t, "(p.price::float / nullif(p.rooms, 0)) desc", cols[1].OrderBy(true))
+}
@@ -0,0 +1,241 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package columns
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ta-ma/prop-filter-app/internal/models"
+)
+
+// Preference tells which value of a column is the best one when comparing properties.
+type Preference int
+
+const (
+	NoPreference Preference = iota
+	Lowest
+	Highest
+)
+
+type Column struct {
+	Key      string
+	Title    string
+	Width    int // preferred width, the available space is shared proportionally to it
+	MinWidth int
+	Priority int // columns with the highest values are the first to be hidden
+	Sql      string
+	// Expressions the column is sorted by, Sql if empty
+	Order  []string
+	Format func(models.PropertyViewModel) string
+	// Numerical value of the column, nil if it has none
+	Value func(models.PropertyViewModel) float64
+	// The SQL expression is not numerical even though the column has a value
+	TextSql bool
+	Best    Preference
+	// Only available when the distance to a point is being calculated
+	NeedsDistance bool
+	// Only available when properties are filtered with fuzzy expressions
+	NeedsSimilarity bool
+	// Not displayed unless explicitly selected
+	Optional bool
+	// Numerical columns the column is made of, exported instead of its text
+	Parts []Column
+}
+
+var latitudeField = Column{Key: "latitude", Sql: "p.latitude", Value: func(r models.PropertyViewModel) float64 { return r.Latitude }}
+var longitudeField = Column{Key: "longitude", Sql: "p.longitude", Value: func(r models.PropertyViewModel) float64 { return r.Longitude }}
+
+var registry = append([]Column{
+	{Key: "description", Title: "Description", Width: 30, MinWidth: 12, Priority: 0, Sql: "p.description",
+		Format: func(r models.PropertyViewModel) string { return r.Description }},
+	{Key: "price", Title: "Price", Width: 10, MinWidth: 8, Priority: 1, Sql: "p.price",
+		Format: func(r models.PropertyViewModel) string { return fmt.Sprintf("$%.2f", r.Price) },
+		Value:  func(r models.PropertyViewModel) float64 { return float64(r.Price) }, Best: Lowest},
+	{Key: "sqft", Title: "Square ft", Width: 10, MinWidth: 7, Priority: 2, Sql: "p.square_footage",
+		Format: func(r models.PropertyViewModel) string { return fmt.Sprintf("%.2f", r.Square_footage) },
+		Value:  func(r models.PropertyViewModel) float64 { return float64(r.Square_footage) }, Best: Highest},
+	{Key: "rooms", Title: "Rooms", Width: 6, MinWidth: 5, Priority: 3, Sql: "p.rooms",
+		Format: func(r models.PropertyViewModel) string { return fmt.Sprintf("%d", r.Rooms) },
+		Value:  func(r models.PropertyViewModel) float64 { return float64(r.Rooms) }, Best: Highest},
+	{Key: "bathrooms", Title: "Bathrooms", Width: 10, MinWidth: 5, Priority: 4, Sql: "p.bathrooms",
+		Format: func(r models.PropertyViewModel) string { return fmt.Sprintf("%d", r.Bathrooms) },
+		Value:  func(r models.PropertyViewModel) float64 { return float64(r.Bathrooms) }, Best: Highest},
+	{Key: "lighting", Title: "Lighting", Width: 10, MinWidth: 6, Priority: 6, Sql: "p.lighting",
+		Format: func(r models.PropertyViewModel) string { return r.Lighting },
+		Value:  lightingLevel, Best: Highest, TextSql: true},
+	{Key: "location", Title: "Location", Width: 16, MinWidth: 10, Priority: 7, Sql: "p.latitude, p.longitude",
+		Order: []string{"p.latitude", "p.longitude"}, Parts: []Column{latitudeField, longitudeField},
+		Format: func(r models.PropertyViewModel) string { return fmt.Sprintf("(%.2f,%.2f)", r.Latitude, r.Longitude) }},
+	{Key: "distance", Title: "Distance", Width: 10, MinWidth: 7, Priority: 1, Sql: "d.dist",
+		Format: func(r models.PropertyViewModel) string { return fmt.Sprintf("%.2f", r.Dist) },
+		Value:  func(r models.PropertyViewModel) float64 { return float64(r.Dist) }, Best: Lowest,
+		NeedsDistance: true},
+	{Key: "amenities", Title: "Amenities", Width: 20, MinWidth: 9, Priority: 5, Sql: "p.amenities",
+		Format: func(r models.PropertyViewModel) string { return r.Amenities },
+		Value:  amenitiesCount, Best: Highest, TextSql: true},
+	{Key: "similarity", Title: "Similarity", Width: 10, MinWidth: 6, Priority: 1, Sql: "s.similarity",
+		Format: func(r models.PropertyViewModel) string { return fmt.Sprintf("%.2f", r.Similarity) },
+		Value:  func(r models.PropertyViewModel) float64 { return float64(r.Similarity) }, Best: Highest,
+		NeedsSimilarity: true, Optional: true},
+}, computed...)
+
+// Available returns every column that can be displayed, in their default order.
+func Available(calcDistance bool, calcSimilarity bool) []Column {
+	result := make([]Column, 0, len(registry))
+	for _, c := range registry {
+		if (c.NeedsDistance && !calcDistance) || (c.NeedsSimilarity && !calcSimilarity) {
+			continue
+		}
+		result = append(result, c)
+	}
+
+	return result
+}
+
+// Default returns the columns displayed when none have been selected.
+func Default(calcDistance bool, calcSimilarity bool) []Column {
+	result := make([]Column, 0, len(registry))
+	for _, c := range Available(calcDistance, calcSimilarity) {
+		if !c.Optional {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+// Parse returns the columns listed in a comma separated list of keys, in the same order.
+// If the list is empty, the default columns are returned.
+func Parse(keys string, calcDistance bool, calcSimilarity bool) ([]Column, error) {
+	if strings.TrimSpace(keys) == "" {
+		return Default(calcDistance, calcSimilarity), nil
+	}
+
+	result := make([]Column, 0)
+	selected := make(map[string]bool)
+	for _, key := range strings.Split(keys, ",") {
+		key = strings.ToLower(strings.TrimSpace(key))
+		c, ok := find(key)
+		if !ok {
+			return nil, fmt.Errorf(`unknown column "%s", available columns are: %s`, key, strings.Join(Keys(), ", "))
+		}
+		if c.NeedsDistance && !calcDistance {
+			return nil, fmt.Errorf(`column "%s" requires the distance parameter`, key)
+		}
+		if c.NeedsSimilarity && !calcSimilarity {
+			return nil, fmt.Errorf(`column "%s" requires a fuzzy filter expression`, key)
+		}
+		if selected[key] {
+			return nil, fmt.Errorf(`column "%s" is listed more than once`, key)
+		}
+
+		selected[key] = true
+		result = append(result, c)
+	}
+
+	return result, nil
+}
+
+// Keys returns the keys of all registered columns.
+func Keys() []string {
+	keys := make([]string, len(registry))
+	for i, c := range registry {
+		keys[i] = c.Key
+	}
+
+	return keys
+}
+
+// numerical reports whether the column can be used in numerical expressions over the properties.
+func (c Column) numerical() bool {
+	return c.Value != nil && !c.TextSql && !c.NeedsSimilarity
+}
+
+// OrderBy returns the SQL expression to sort by the column.
+func (c Column) OrderBy(desc bool) string {
+	direction := "asc"
+	if desc {
+		direction = "desc"
+	}
+
+	exprs := c.Order
+	if len(exprs) == 0 {
+		exprs = []string{c.Sql}
+	}
+
+	parts := make([]string, len(exprs))
+	for i, expr := range exprs {
+		parts[i] = fmt.Sprintf("%s %s", expr, direction)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Sort is the column the properties are sorted by.
+type Sort struct {
+	Column Column
+	Desc   bool
+}
+
+// ParseSort parses a column to sort by, such as "price" or "ppsf:desc". Returns nil if the
+// value is empty.
+func ParseSort(value string, calcDistance bool, calcSimilarity bool) (*Sort, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	key, direction, _ := strings.Cut(strings.ToLower(strings.TrimSpace(value)), ":")
+	c, ok := find(key)
+	switch {
+	case !ok:
+		return nil, fmt.Errorf(`unknown column "%s", available columns are: %s`, key, strings.Join(Keys(), ", "))
+	case c.NeedsDistance && !calcDistance:
+		return nil, fmt.Errorf(`column "%s" requires the distance parameter`, key)
+	case c.NeedsSimilarity && !calcSimilarity:
+		return nil, fmt.Errorf(`column "%s" requires a fuzzy filter expression`, key)
+	case direction != "" && direction != "asc" && direction != "desc":
+		return nil, fmt.Errorf(`sort direction "%s" must be "asc" or "desc"`, direction)
+	}
+
+	return &Sort{Column: c, Desc: direction == "desc"}, nil
+}
+
+// Row formats the values of a property for the given columns.
+func Row(r models.PropertyViewModel, cols []Column) []string {
+	row := make([]string, len(cols))
+	for i, c := range cols {
+		row[i] = c.Format(r)
+	}
+
+	return row
+}
+
+func find(key string) (Column, bool) {
+	for _, c := range registry {
+		if c.Key == key {
+			return c, true
+		}
+	}
+
+	return Column{}, false
+}
+
+// lightingLevel returns the position of the lighting among the possible values, from low to high.
+func lightingLevel(r models.PropertyViewModel) float64 {
+	for i, l := range models.GetLightingValues() {
+		if l == r.Lighting {
+			return float64(i)
+		}
+	}
+
+	return -1
+}
+
+func amenitiesCount(r models.PropertyViewModel) float64 {
+	if strings.TrimSpace(r.Amenities) == "" {
+		return 0
+	}
+
+	return float64(len(strings.Split(r.Amenities, ",")))
+}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package columns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	type testCase struct {
+		keys           string
+		calcDistance   bool
+		calcSimilarity bool
+		expected       []string
+		errExpected    bool
+	}
+
+	testCases := []testCase{
+		{keys: "", calcDistance: false, errExpected: false,
+			expected: []string{"description", "price", "sqft", "rooms", "bathrooms", "lighting", "location", "amenities"}},
+		{keys: "", calcDistance: true, errExpected: false,
+			expected: []string{"description", "price", "sqft", "rooms", "bathrooms", "lighting", "location", "distance", "amenities"}},
+		{keys: "price,ppsf,rooms", calcDistance: false, expected: []string{"price", "ppsf", "rooms"}, errExpected: false},
+		{keys: " Rooms , PRICE", calcDistance: false, expected: []string{"rooms", "price"}, errExpected: false},
+		{keys: "distance,price", calcDistance: true, expected: []string{"distance", "price"}, errExpected: false},
+		{keys: "distance,price", calcDistance: false, errExpected: true},
+		{keys: "price,size", calcDistance: false, errExpected: true},
+		{keys: "price,price", calcDistance: false, errExpected: true},
+		{keys: "price,", calcDistance: false, errExpected: true},
+		{keys: "similarity,price", calcSimilarity: true, expected: []string{"similarity", "price"}, errExpected: false},
+		{keys: "similarity,price", calcSimilarity: false, errExpected: true},
+	}
+
+	for _, test := range testCases {
+		actual, err := Parse(test.keys, test.calcDistance, test.calcSimilarity)
+
+		if test.errExpected {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+
+		keys := make([]string, len(actual))
+		for i, c := range actual {
+			keys[i] = c.Key
+		}
+		assert.Equal(t, test.expected, keys)
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	type testCase struct {
+		key      string
+		desc     bool
+		expected string
+	}
+
+	testCases := []testCase{
+		{key: "price", desc: false, expected: "p.price asc"},
+		{key: "location", desc: true, expected: "p.latitude desc, p.longitude desc"},
+		{key: "rooms_per_bathroom", desc: true, expected: "(p.rooms::float / nullif(p.bathrooms, 0)) desc"},
+		{key: "ppsf", desc: false, expected: "(p.price / nullif(p.square_footage, 0)) asc"},
+	}
+
+	for _, test := range testCases {
+		c, ok := find(test.key)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, c.OrderBy(test.desc))
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	type testCase struct {
+		value        string
+		calcDistance bool
+		expectedKey  string
+		expectedDesc bool
+		errExpected  bool
+	}
+
+	testCases := []testCase{
+		{value: "price", expectedKey: "price", expectedDesc: false, errExpected: false},
+		{value: " PPSF:desc ", expectedKey: "ppsf", expectedDesc: true, errExpected: false},
+		{value: "rooms:asc", expectedKey: "rooms", expectedDesc: false, errExpected: false},
+		{value: "distance:desc", calcDistance: true, expectedKey: "distance", expectedDesc: true, errExpected: false},
+		{value: "distance", calcDistance: false, errExpected: true},
+		{value: "size", errExpected: true},
+		{value: "price:down", errExpected: true},
+	}
+
+	for _, test := range testCases {
+		actual, err := ParseSort(test.value, test.calcDistance, false)
+
+		if test.errExpected {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, test.expectedKey, actual.Column.Key)
+		assert.Equal(t, test.expectedDesc, actual.Desc)
+	}
+
+	actual, err := ParseSort("", false, false)
+	assert.NoError(t, err)
+	assert.Nil(t, actual)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package columns
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ta-ma/prop-filter-app/internal/filter"
+)
+
+var conditionRegex = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s*(.+?)\s*$`)
+
+// Condition is a numerical filter expression, such as "<300", over the value of a column.
+type Condition struct {
+	Column Column
+	Expr   string
+	// Position of Expr in the conditions it was parsed from, in bytes
+	Offset int
+}
+
+// ParseWhere parses conditions over numerical columns separated by ";", such as
+// "ppsf < 300; amenity_count >= 2".
+func ParseWhere(where string, calcDistance bool) ([]Condition, error) {
+	if strings.TrimSpace(where) == "" {
+		return nil, nil
+	}
+
+	var conditions []Condition
+	offset := 0
+	for _, part := range strings.Split(where, filter.Separator) {
+		match := conditionRegex.FindStringSubmatchIndex(part)
+		if match == nil {
+			return nil, fmt.Errorf(`condition "%s" must have the format "<column> <op> <value>"`, strings.TrimSpace(part))
+		}
+
+		key := strings.ToLower(part[match[2]:match[3]])
+		c, ok := find(key)
+		switch {
+		case !ok:
+			return nil, fmt.Errorf(`unknown column "%s", available columns are: %s`, key, strings.Join(Keys(), ", "))
+		case !c.numerical():
+			return nil, fmt.Errorf(`column "%s" can't be used in conditions`, key)
+		case c.NeedsDistance && !calcDistance:
+			return nil, fmt.Errorf(`column "%s" requires the distance parameter`, key)
+		}
+
+		conditions = append(conditions, Condition{Column: c, Expr: part[match[4]:match[5]], Offset: offset + match[4]})
+		offset += len(part) + len(filter.Separator)
+	}
+
+	return conditions, nil
+}
+
+// WhereError returns the error of translating the condition, pointing to its position in the
+// conditions it was parsed from.
+func (c Condition) WhereError(where string, err error) error {
+	var exprErr *filter.ExprError
+	if !errors.As(err, &exprErr) {
+		return fmt.Errorf(`condition on column "%s": %w`, c.Column.Key, err)
+	}
+
+	positioned := *exprErr
+	positioned.Expr = where
+	positioned.Pos += utf8.RuneCountInString(where[:c.Offset])
+	positioned.Source = fmt.Sprintf(`condition on column "%s"`, c.Column.Key)
+	return &positioned
+}
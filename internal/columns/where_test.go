@@ -0,0 +1,79 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package columns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ta-ma/prop-filter-app/internal/filter"
+)
+
+func TestParseWhere(t *testing.T) {
+	type testCase struct {
+		where        string
+		calcDistance bool
+		expected     []string
+		errExpected  bool
+	}
+
+	testCases := []testCase{
+		{where: "", expected: []string{}, errExpected: false},
+		{where: "ppsf < 300", expected: []string{"(p.price / nullif(p.square_footage, 0)) < 300"}, errExpected: false},
+		{where: "AMENITY_COUNT>=2; price 250k..475k",
+			expected: []string{"p.amenity_count >=2",
+				"p.price 250k..475k"}, errExpected: false},
+		{where: "distance < 10", calcDistance: true, expected: []string{"d.dist < 10"}, errExpected: false},
+		{where: "distance < 10", calcDistance: false, errExpected: true},
+		{where: "description = test", errExpected: true},
+		{where: "lighting > 1", errExpected: true},
+		{where: "size < 10", errExpected: true},
+		{where: "< 10", errExpected: true},
+	}
+
+	for _, test := range testCases {
+		actual, err := ParseWhere(test.where, test.calcDistance)
+
+		if test.errExpected {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+
+		conditions := make([]string, len(actual))
+		for i, c := range actual {
+			conditions[i] = c.Column.Sql + " " + c.Expr
+		}
+		assert.Equal(t, test.expected, conditions)
+	}
+}
+
+func TestParseWhereFormula(t *testing.T) {
+	original := registry
+	defer func() { registry = original }()
+	assert.NoError(t, RegisterFormulas(map[string]string{"cost2": "price * 2"}))
+
+	actual, err := ParseWhere("cost2 < 5", false)
+	assert.NoError(t, err)
+	assert.Len(t, actual, 1)
+	assert.Equal(t, "cost2", actual[0].Column.Key)
+	assert.Equal(t, "< 5", actual[0].Expr)
+}
+
+func TestWhereError(t *testing.T) {
+	where := "ppsf < 300; amenity_count >= 2x "
+	conditions, err := ParseWhere(where, false)
+	assert.NoError(t, err)
+	assert.Equal(t, ">= 2x", conditions[1].Expr)
+	assert.Equal(t, 26, conditions[1].Offset)
+
+	translator := filter.Translator{}
+	translator.Init()
+	translator.Translate(conditions[1].Column.Sql, conditions[1].Expr, filter.Num)
+
+	expected := `condition on column "amenity_count": filter expression "ppsf < 300; amenity_count >= 2x " is not valid at position 29: " 2x" is not a valid number
+  ppsf < 300; amenity_count >= 2x 
+                              ^`
+	assert.EqualError(t, conditions[1].WhereError(where, translator.Err), expected)
+}
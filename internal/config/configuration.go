@@ -4,8 +4,22 @@ Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
 package config
 
 type Cli struct {
-	TrimLength   int
-	UseOldRender bool
+	TrimLength    int
+	UseOldRender  bool
+	PageCacheSize int
+	Points        []Point
+	Theme         string
+	KeyMap        map[string][]string
+	// Columns calculated with arithmetic formulas, mapped by their name
+	Formulas map[string]string
+	// File where the fields filtered by each query are recorded, disabled if empty
+	QueryLog string
+}
+
+type Point struct {
+	Name      string
+	Latitude  float64
+	Longitude float64
 }
 
 type DbConfig struct {
@@ -16,6 +30,14 @@ type DbConfig struct {
 	DbName       string
 	SeedDatabase bool
 	SeedEntries  uint
+	// Minimum word similarity, between 0 and 1, of the fuzzy filters
+	FuzzyThreshold float64
+	// Maximum time in milliseconds the queries listing properties can take, unlimited if 0
+	StatementTimeout uint
+	// Whether the connection uses TLS, such as "disable" or "require"
+	SslMode string
+	// Additional connection parameters, such as sslrootcert
+	ConnParams map[string]string
 }
 
 type Configuration struct {
@@ -8,17 +8,13 @@ import (
 	"os"
 )
 
-func Read(filePath string) (Configuration, error) {
-	var configuration Configuration
+// Read reads a JSON configuration file over the given configuration, so the settings missing
+// from the file keep their values.
+func Read(filePath string, configuration *Configuration) error {
 	configText, err := os.ReadFile(filePath)
 	if err != nil {
-		return configuration, err
+		return err
 	}
 
-	err = json.Unmarshal(configText, &configuration)
-	if err != nil {
-		return configuration, err
-	}
-
-	return configuration, nil
+	return json.Unmarshal(configText, configuration)
 }
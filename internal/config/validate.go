@@ -0,0 +1,40 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// Validate checks the database settings without connecting to it, returning every problem found.
+func (c DbConfig) Validate() error {
+	var errs []error
+	if c.Host == "" {
+		errs = append(errs, fmt.Errorf("Host is required"))
+	}
+	if c.Port == 0 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("Port must be between 1 and 65535, got %d", c.Port))
+	}
+	if c.DbName == "" {
+		errs = append(errs, fmt.Errorf("DbName is required"))
+	}
+	if c.PgUser == "" {
+		errs = append(errs, fmt.Errorf("PgUser is required"))
+	}
+	if !slices.Contains(sslModes, c.SslMode) {
+		errs = append(errs, fmt.Errorf(`SslMode must be one of %v, got "%s"`, sslModes, c.SslMode))
+	}
+	if c.FuzzyThreshold < 0 || c.FuzzyThreshold > 1 {
+		errs = append(errs, fmt.Errorf("FuzzyThreshold must be between 0 and 1, got %g", c.FuzzyThreshold))
+	}
+	if c.SeedDatabase && c.SeedEntries == 0 {
+		errs = append(errs, fmt.Errorf("SeedEntries must be greater than 0 when SeedDatabase is true"))
+	}
+
+	return errors.Join(errs...)
+}
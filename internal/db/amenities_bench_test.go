@@ -0,0 +1,91 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package db
+
+import (
+	"os"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Amenities of each property aggregated as text, as they were listed before the listings view
+const stringAggStatement = "left join (" +
+	"select pa.property_id as id, STRING_AGG(a.description, ', ') amenities from properties_amenities pa " +
+	"join amenities a on pa.amenity_id = a.id " +
+	"group by pa.property_id " +
+	") a on p.id = a.id"
+
+const amenitiesSubquery = "select 1 from properties_amenities pa join amenities am on pa.amenity_id = am.id " +
+	"where pa.property_id = p.id"
+
+// connectBenchmark connects to the seeded database set in PROPFILTER_BENCH_DSN, skipping the
+// benchmark if it is not set.
+func connectBenchmark(b *testing.B) *Repository {
+	dsn := os.Getenv("PROPFILTER_BENCH_DSN")
+	if dsn == "" {
+		b.Skip("PROPFILTER_BENCH_DSN is not set")
+	}
+
+	conn, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		b.Skip("Could not connect to Postgres:", err)
+	}
+
+	r := &Repository{conn: conn}
+	if r.hasListings = r.hasListingsView(); !r.hasListings {
+		b.Skip("The listings view does not exist, seed the database or run db refresh")
+	}
+	return r
+}
+
+// BenchmarkAmenityFilter counts the properties with a pool and a garage and without a
+// waterfront, matching the amenities aggregated as text, looking them up with subqueries, and
+// with the array and bitmask of the listings view.
+func BenchmarkAmenityFilter(b *testing.B) {
+	r := connectBenchmark(b)
+
+	benchmarks := []struct {
+		name  string
+		query func() *gorm.DB
+	}{
+		{name: "string_agg", query: func() *gorm.DB {
+			return r.conn.Table("properties as p").
+				Joins("join lightings l on p.lighting_id = l.id").
+				Joins(stringAggStatement).
+				Where("lower(a.amenities) like lower('%pool%') and lower(a.amenities) like lower('%garage%') and " +
+					"coalesce(lower(a.amenities), '') not like lower('%waterfront%')")
+		}},
+		{name: "exists", query: func() *gorm.DB {
+			return r.conn.Table("properties as p").
+				Joins("join lightings l on p.lighting_id = l.id").
+				Where("exists (" + amenitiesSubquery + " and am.description = 'pool') and " +
+					"exists (" + amenitiesSubquery + " and am.description = 'garage') and " +
+					"not exists (" + amenitiesSubquery + " and am.description in ('waterfront'))")
+		}},
+		{name: "array", query: func() *gorm.DB {
+			return r.conn.Table(r.listingsTable()).
+				Where("p.amenity_list @> array['pool', 'garage'] and not p.amenity_list && array['waterfront']")
+		}},
+		{name: "bitmask", query: func() *gorm.DB {
+			return r.conn.Table(r.listingsTable()).
+				Where("p.amenity_mask & (select bit_or(1 << (id::int - 1)) from amenities where description in ('pool', 'garage')) = " +
+					"(select bit_or(1 << (id::int - 1)) from amenities where description in ('pool', 'garage')) and " +
+					"p.amenity_mask & (select bit_or(1 << (id::int - 1)) from amenities where description = 'waterfront') = 0")
+		}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var count int64
+				if err := bm.query().Count(&count).Error; err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
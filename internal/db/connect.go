@@ -0,0 +1,76 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/ta-ma/prop-filter-app/internal/config"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Connection attempts made while the server can't be reached, waiting twice as long after each one
+const connectAttempts = 5
+const connectBackoff = 500 * time.Millisecond
+
+// connect opens a connection to Postgres, retrying with an exponential backoff while the server
+// is unreachable or starting up. Errors which retrying wouldn't fix, such as wrong credentials,
+// are returned right away.
+func connect(ctx context.Context, dsn string, dbConfig *config.DbConfig) (*gorm.DB, error) {
+	backoff := connectBackoff
+	for attempt := 1; ; attempt++ {
+		conn, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+			Logger: logger.Default.LogMode(logger.Silent),
+		})
+		if err == nil {
+			return conn, nil
+		}
+
+		reason, retry := diagnoseConnectError(err)
+		if !retry || attempt == connectAttempts {
+			return nil, fmt.Errorf("could not connect to Postgres at %s:%d, %s: %w", dbConfig.Host, dbConfig.Port, reason, err)
+		}
+
+		fmt.Printf("DB: Could not connect (%s), retrying in %s (attempt %d of %d)...\n", reason, backoff, attempt, connectAttempts)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// diagnoseConnectError describes the cause of a connection error in terms of the configuration,
+// and tells whether connecting again could succeed.
+func diagnoseConnectError(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "28P01", "28000":
+			return "authentication failed, check PgUser and PgPassword", false
+		case "3D000":
+			return "the database does not exist, check DbName", false
+		case "57P03":
+			return "the server is starting up", true
+		case "53300":
+			return "the server has too many connections", true
+		}
+		return pgErr.Message, false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return "the host is unreachable, check Host and Port", true
+	}
+
+	return "unexpected error", false
+}
@@ -0,0 +1,64 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package db
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/ta-ma/prop-filter-app/internal/config"
+)
+
+func TestDiagnoseConnectError(t *testing.T) {
+	type testCase struct {
+		err           error
+		expected      string
+		retryExpected bool
+	}
+
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")}
+	testCases := []testCase{
+		{err: fmt.Errorf("failed to connect: %w", refused), expected: "the host is unreachable, check Host and Port", retryExpected: true},
+		{err: &pgconn.PgError{Code: "28P01"}, expected: "authentication failed, check PgUser and PgPassword", retryExpected: false},
+		{err: fmt.Errorf("failed to connect: %w", &pgconn.PgError{Code: "3D000"}),
+			expected: "the database does not exist, check DbName", retryExpected: false},
+		{err: &pgconn.PgError{Code: "57P03"}, expected: "the server is starting up", retryExpected: true},
+		{err: &pgconn.PgError{Code: "53300"}, expected: "the server has too many connections", retryExpected: true},
+		{err: &pgconn.PgError{Code: "42501", Message: "permission denied for database"},
+			expected: "permission denied for database", retryExpected: false},
+		{err: errors.New("something else"), expected: "unexpected error", retryExpected: false},
+	}
+
+	for _, test := range testCases {
+		actual, retry := diagnoseConnectError(test.err)
+
+		assert.Equal(t, test.expected, actual)
+		assert.Equal(t, test.retryExpected, retry)
+	}
+}
+
+func TestConnectionUrl(t *testing.T) {
+	dbConfig := &config.DbConfig{Host: "db.example.com", Port: 6543, PgUser: "admin@corp", PgPassword: `a b'c/d?e@f`,
+		DbName: "filter prop", SslMode: "disable", FuzzyThreshold: 0.4, ConnParams: map[string]string{"application_name": "prop filter"}}
+
+	parsed, err := pgconn.ParseConfig(connectionUrl(dbConfig))
+	assert.NoError(t, err)
+	assert.Equal(t, "db.example.com", parsed.Host)
+	assert.Equal(t, uint16(6543), parsed.Port)
+	assert.Equal(t, "admin@corp", parsed.User)
+	assert.Equal(t, `a b'c/d?e@f`, parsed.Password)
+	assert.Equal(t, "filter prop", parsed.Database)
+	assert.Nil(t, parsed.TLSConfig)
+	assert.Equal(t, "prop filter", parsed.RuntimeParams["application_name"])
+	assert.Equal(t, "-c pg_trgm.word_similarity_threshold=0.4", parsed.RuntimeParams["options"])
+
+	dbConfig.SslMode = "require"
+	parsed, err = pgconn.ParseConfig(connectionUrl(dbConfig))
+	assert.NoError(t, err)
+	assert.NotNil(t, parsed.TLSConfig)
+}
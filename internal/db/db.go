@@ -4,48 +4,79 @@ Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
 package db
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/ta-ma/prop-filter-app/internal/config"
 	"github.com/ta-ma/prop-filter-app/internal/models"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
-var db *gorm.DB
-
-func Initialize(dbConfig *config.DbConfig) {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable",
-		dbConfig.Host, dbConfig.PgUser, dbConfig.PgPassword, dbConfig.DbName, dbConfig.Port)
+// Properties are listed in this order so pages are stable between queries
+const defaultOrder = "p.id"
+
+// Repository queries the properties stored in a Postgres database.
+type Repository struct {
+	conn *gorm.DB
+	// Maximum time the queries listing properties can take, unlimited if 0
+	statementTimeout time.Duration
+	// Whether the listings view exists, otherwise its definition is queried as a subquery
+	hasListings bool
+	// Whether the properties table has the columns required by full text searches
+	hasFullText bool
+}
 
-	var err error
-	db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
-	})
+// Open connects to the database. It doesn't seed it, see SeedDatabase.
+func Open(ctx context.Context, dbConfig *config.DbConfig) (*Repository, error) {
+	conn, err := connect(ctx, connectionUrl(dbConfig), dbConfig)
 	if err != nil {
-		fmt.Println("ERROR: Could not connect to Postgres:", err)
-		panic("Failed to connect to Postgres database!")
+		return nil, err
 	}
 
-	if dbConfig.SeedDatabase {
-		SeedDatabase(db, dbConfig.SeedEntries)
-	}
+	r := &Repository{conn: conn, statementTimeout: time.Duration(dbConfig.StatementTimeout) * time.Millisecond}
+	r.hasListings = r.hasListingsView()
+	r.hasFullText = r.hasFullTextColumns()
+	return r, nil
 }
 
-func QueryProperties(queryFilter string, limit int, offset int, calcDist bool, distX string, distY string) ([]models.PropertyViewModel, error) {
-	if db == nil {
-		return []models.PropertyViewModel{}, fmt.Errorf("database connection has not been initialized")
+// connectionUrl returns the URL to connect to the database, with its values escaped so they
+// can have any character.
+func connectionUrl(dbConfig *config.DbConfig) string {
+	params := url.Values{}
+	for name, value := range dbConfig.ConnParams {
+		params.Set(name, value)
+	}
+	if dbConfig.SslMode != "" {
+		params.Set("sslmode", dbConfig.SslMode)
+	}
+	if dbConfig.FuzzyThreshold > 0 {
+		// Used by the pg_trgm word similarity operator of fuzzy filters
+		params.Set("options", fmt.Sprintf("-c pg_trgm.word_similarity_threshold=%g", dbConfig.FuzzyThreshold))
 	}
 
-	var queryResult []models.PropertyViewModel
-	var queryBuilder *gorm.DB
-	if calcDist {
-		queryBuilder = getDistanceQuery(queryFilter, distX, distY)
-	} else {
-		queryBuilder = getStandardQuery(queryFilter)
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(dbConfig.PgUser, dbConfig.PgPassword),
+		Host:     net.JoinHostPort(dbConfig.Host, strconv.FormatUint(uint64(dbConfig.Port), 10)),
+		Path:     "/" + dbConfig.DbName,
+		RawQuery: params.Encode(),
 	}
-	err := queryBuilder.Limit(limit).Offset(offset).Scan(&queryResult).Error
+	return u.String()
+}
+
+// QueryProperties lists a page of the properties. If similarity is not empty, it is the SQL
+// expression of the score selected as the similarity of each property.
+func (r *Repository) QueryProperties(ctx context.Context, queryFilter string, order string, limit int, offset int, calcDist bool, distX string, distY string, similarity string) ([]models.PropertyViewModel, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var queryResult []models.PropertyViewModel
+	queryBuilder := r.getPropertiesQuery(ctx, queryFilter, calcDist, distX, distY, similarity)
+	err := queryBuilder.Order(getOrder(order)).Limit(limit).Offset(offset).Scan(&queryResult).Error
 
 	if err != nil {
 		return []models.PropertyViewModel{}, err
@@ -54,15 +85,34 @@ func QueryProperties(queryFilter string, limit int, offset int, calcDist bool, d
 	return queryResult, nil
 }
 
-func GetPropertiesCount(queryFilter string, calcDist bool, distX string, distY string) (int, error) {
-	var count int64
-	var queryBuilder *gorm.DB
-	if calcDist {
-		queryBuilder = getDistanceQuery(queryFilter, distX, distY)
-	} else {
-		queryBuilder = getStandardQuery(queryFilter)
+// GetPropertyPosition returns the position (starting at 1) of a property among the ones
+// listed by QueryProperties with the same filter and order.
+func (r *Repository) GetPropertyPosition(ctx context.Context, id uint, queryFilter string, order string, calcDist bool, distX string, distY string, similarity string) (int, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	queryBuilder := r.getPropertiesQuery(ctx, queryFilter, calcDist, distX, distY, similarity)
+	positions := queryBuilder.Select(fmt.Sprintf("p.id, row_number() over (order by %s) as pos", getOrder(order)))
+
+	var position []int
+	err := r.conn.WithContext(ctx).Table("(?) as r", positions).Select("r.pos").Where("r.id = ?", id).Scan(&position).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(position) == 0 {
+		return 0, fmt.Errorf("property %d is not listed with the current filter", id)
 	}
 
+	return position[0], nil
+}
+
+func (r *Repository) GetPropertiesCount(ctx context.Context, queryFilter string, calcDist bool, distX string, distY string) (int, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var count int64
+	queryBuilder := r.getPropertiesQuery(ctx, queryFilter, calcDist, distX, distY, "")
+
 	err := queryBuilder.Count(&count).Error
 
 	if err != nil {
@@ -71,49 +121,99 @@ func GetPropertiesCount(queryFilter string, calcDist bool, distX string, distY s
 	return int(count), nil
 }
 
-func getStandardQuery(queryFilter string) *gorm.DB {
-	selectStatement :=
-		"p.description, p.price, p.square_footage, p.rooms, p.bathrooms, p.latitude, p.longitude," +
-			"l.description as lighting, a.amenities"
-
-	amenitiesStatement :=
-		"join (" +
-			"select p.id, STRING_AGG(a.description, ', ') amenities from properties p " +
-			"left join properties_amenities pa on p.id = pa.property_id " +
-			"join amenities a on pa.amenity_id = a.id " +
-			"group by p.id " +
-			") a on p.id = a.id"
-
-	return db.Table("properties as p").
-		Select(selectStatement).
-		Joins("join lightings l on p.lighting_id = l.id").
-		Joins(amenitiesStatement).
-		Where(queryFilter)
+// QuerySimilarProperties lists the properties closest to the given one which have a similar
+// amount of rooms and bathrooms and a similar price. Their distance to it is calculated.
+func (r *Repository) QuerySimilarProperties(ctx context.Context, property models.PropertyViewModel, limit int) ([]models.PropertyViewModel, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var queryResult []models.PropertyViewModel
+	distX := fmt.Sprintf("%f", property.Latitude)
+	distY := fmt.Sprintf("%f", property.Longitude)
+	rooms := int(property.Rooms)
+	bathrooms := int(property.Bathrooms)
+
+	err := r.getDistanceQuery(ctx, "", distX, distY, "").
+		Where("p.id <> ?", property.ID).
+		Where("p.rooms between ? and ?", rooms-1, rooms+1).
+		Where("p.bathrooms between ? and ?", bathrooms-1, bathrooms+1).
+		Where("p.price between ? and ?", property.Price*0.75, property.Price*1.25).
+		Order("d.dist").
+		Limit(limit).
+		Scan(&queryResult).Error
+
+	if err != nil {
+		return []models.PropertyViewModel{}, err
+	}
+
+	return queryResult, nil
+}
+
+func (r *Repository) getPropertiesQuery(ctx context.Context, queryFilter string, calcDist bool, distX string, distY string, similarity string) *gorm.DB {
+	if calcDist {
+		return r.getDistanceQuery(ctx, queryFilter, distX, distY, similarity)
+	}
+
+	return r.getStandardQuery(ctx, queryFilter, similarity)
+}
+
+// withTimeout returns a context which is cancelled after the statement timeout, if it is set.
+func (r *Repository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.statementTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, r.statementTimeout)
 }
 
-func getDistanceQuery(queryFilter string, distX string, distY string) *gorm.DB {
-	selectStatement :=
-		"p.description, p.price, p.square_footage, p.rooms, p.bathrooms, p.latitude, p.longitude," +
-			"l.description as lighting, a.amenities, d.dist"
+func getOrder(order string) string {
+	if order == "" {
+		return defaultOrder
+	}
 
-	amenitiesStatement :=
-		"join (" +
-			"select p.id, STRING_AGG(a.description, ', ') amenities from properties p " +
-			"left join properties_amenities pa on p.id = pa.property_id " +
-			"join amenities a on pa.amenity_id = a.id " +
-			"group by p.id " +
-			") a on p.id = a.id"
+	// The ID breaks ties so pages are stable
+	return order + ", " + defaultOrder
+}
 
+const listingsSelect = "p.id, p.description, p.price, p.square_footage, p.rooms, p.bathrooms, p.latitude, p.longitude, " +
+	"p.lighting, p.amenities"
+
+func (r *Repository) getStandardQuery(ctx context.Context, queryFilter string, similarity string) *gorm.DB {
+	queryBuilder := r.conn.WithContext(ctx).Table(r.listingsTable())
+
+	return withSimilarity(queryBuilder, listingsSelect, similarity).Where(queryFilter)
+}
+
+func (r *Repository) getDistanceQuery(ctx context.Context, queryFilter string, distX string, distY string, similarity string) *gorm.DB {
 	distStatement :=
 		fmt.Sprintf(
-			"join (select id, fn_spheric_distance(%s, %s, latitude, longitude) as dist from properties) d on p.id = d.id",
+			"cross join lateral (select fn_spheric_distance(%s, %s, p.latitude, p.longitude) as dist) d",
 			distX, distY,
 		)
 
-	return db.Table("properties as p").
-		Select(selectStatement).
-		Joins("join lightings l on p.lighting_id = l.id").
-		Joins(distStatement).
-		Joins(amenitiesStatement).
-		Where(queryFilter)
+	queryBuilder := r.conn.WithContext(ctx).Table(r.listingsTable()).
+		Joins(distStatement)
+
+	return withSimilarity(queryBuilder, listingsSelect+", d.dist", similarity).Where(queryFilter)
+}
+
+// withSimilarity selects the similarity score as s.similarity, so it can also be used to sort.
+func withSimilarity(queryBuilder *gorm.DB, selectStatement string, similarity string) *gorm.DB {
+	if similarity == "" {
+		return queryBuilder.Select(selectStatement)
+	}
+
+	return queryBuilder.
+		Select(selectStatement + ", s.similarity").
+		Joins(fmt.Sprintf("cross join lateral (select %s as similarity) s", similarity))
+}
+
+// HasFullTextSearch reports whether the properties table has the columns required by full
+// text searches, which are created when seeding the database.
+func (r *Repository) HasFullTextSearch() bool {
+	return r.hasFullText
+}
+
+func (r *Repository) hasFullTextColumns() bool {
+	return r.conn.Migrator().HasColumn(&models.Property{}, "description_tsv")
 }
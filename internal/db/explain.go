@@ -0,0 +1,73 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/ta-ma/prop-filter-app/internal/models"
+	"gorm.io/gorm"
+)
+
+// QueryExplanation describes the queries run to list a page of properties and how long they took.
+type QueryExplanation struct {
+	CountSql      string
+	PageSql       string
+	Count         int
+	Rows          int
+	CountDuration time.Duration
+	PageDuration  time.Duration
+	// Output of EXPLAIN ANALYZE for the page query, only if it was requested
+	Plan []string
+}
+
+// ExplainQuery runs the same queries as GetPropertiesCount and QueryProperties, returning their
+// SQL with the parameters bound and their timings. If analyze is true, the plan Postgres used
+// for the page query is also returned.
+func (r *Repository) ExplainQuery(ctx context.Context, queryFilter string, order string, limit int, offset int, calcDist bool, distX string, distY string, similarity string, analyze bool) (QueryExplanation, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var explanation QueryExplanation
+	var count int64
+	countQuery := func(dryRun bool) *gorm.DB {
+		return r.getPropertiesQuery(ctx, queryFilter, calcDist, distX, distY, "").Session(&gorm.Session{DryRun: dryRun}).Count(&count)
+	}
+	var props []models.PropertyViewModel
+	pageQuery := func(dryRun bool) *gorm.DB {
+		return r.getPropertiesQuery(ctx, queryFilter, calcDist, distX, distY, similarity).Session(&gorm.Session{DryRun: dryRun}).
+			Order(getOrder(order)).Limit(limit).Offset(offset).Find(&props)
+	}
+
+	explanation.CountSql = r.toSql(countQuery(true))
+	explanation.PageSql = r.toSql(pageQuery(true))
+
+	start := time.Now()
+	if err := countQuery(false).Error; err != nil {
+		return explanation, err
+	}
+	explanation.CountDuration = time.Since(start)
+	explanation.Count = int(count)
+
+	start = time.Now()
+	if err := pageQuery(false).Error; err != nil {
+		return explanation, err
+	}
+	explanation.PageDuration = time.Since(start)
+	explanation.Rows = len(props)
+
+	if analyze {
+		if err := r.conn.WithContext(ctx).Raw("explain analyze " + explanation.PageSql).Scan(&explanation.Plan).Error; err != nil {
+			return explanation, err
+		}
+	}
+
+	return explanation, nil
+}
+
+// toSql returns the SQL of a query built in dry run mode, with its parameters bound.
+func (r *Repository) toSql(query *gorm.DB) string {
+	return r.conn.Dialector.Explain(query.Statement.SQL.String(), query.Statement.Vars...)
+}
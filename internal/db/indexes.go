@@ -0,0 +1,83 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Index is a btree index over some columns of a table.
+type Index struct {
+	Table   string
+	Columns []string
+}
+
+// IndexedFields maps the filterable fields to the column of the listings view indexed for them.
+var IndexedFields = map[string]string{
+	"p.price":          "price",
+	"p.rooms":          "rooms",
+	"p.bathrooms":      "bathrooms",
+	"p.square_footage": "square_footage",
+	"p.latitude":       "latitude",
+	"p.longitude":      "longitude",
+	"p.lighting":       "lighting",
+}
+
+// Indexes on the filterable columns of the listings view and the join table, which is used by
+// the amenity filters. The primary key of properties_amenities already covers
+// (property_id, amenity_id).
+var DefaultIndexes = []Index{
+	{Table: listingsView, Columns: []string{"price"}},
+	{Table: listingsView, Columns: []string{"rooms"}},
+	{Table: listingsView, Columns: []string{"bathrooms"}},
+	{Table: listingsView, Columns: []string{"square_footage"}},
+	{Table: listingsView, Columns: []string{"lighting"}},
+	{Table: listingsView, Columns: []string{"latitude", "longitude"}},
+	{Table: "properties_amenities", Columns: []string{"amenity_id", "property_id"}},
+}
+
+// ListingsIndex returns the index over columns of the listings view.
+func ListingsIndex(columns []string) Index {
+	return Index{Table: listingsView, Columns: columns}
+}
+
+// Name returns the name of the index, such as "idx_properties_price_rooms".
+func (i Index) Name() string {
+	return fmt.Sprintf("idx_%s_%s", i.Table, strings.Join(i.Columns, "_"))
+}
+
+// HasIndex tells whether an index with the same name exists.
+func (r *Repository) HasIndex(ctx context.Context, index Index) bool {
+	return r.conn.WithContext(ctx).Migrator().HasIndex(index.Table, index.Name())
+}
+
+// CreateIndex creates the index if it doesn't exist.
+func (r *Repository) CreateIndex(ctx context.Context, index Index) error {
+	return r.conn.WithContext(ctx).Exec(fmt.Sprintf("create index if not exists %s on %s (%s)",
+		index.Name(), index.Table, strings.Join(index.Columns, ", "))).Error
+}
+
+// indexDefinitions returns the statements creating the indexes of the listings view and the
+// join table, such as the ones created by db analyze, so they can be recreated after seeding.
+func (r *Repository) indexDefinitions(ctx context.Context) ([]string, error) {
+	var definitions []string
+	err := r.conn.WithContext(ctx).Raw("select indexdef from pg_indexes where schemaname = current_schema() and tablename in ?",
+		[]string{listingsView, "properties_amenities"}).Scan(&definitions).Error
+
+	return definitions, err
+}
+
+// restoreIndexes creates the indexes from their definitions, skipping the ones which exist.
+func (r *Repository) restoreIndexes(ctx context.Context, definitions []string) error {
+	for _, definition := range definitions {
+		statement := strings.Replace(definition, " INDEX ", " INDEX IF NOT EXISTS ", 1)
+		if err := r.conn.WithContext(ctx).Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// Materialized view with the shape of PropertyViewModel, so listing properties doesn't join
+// the lightings and aggregate the amenities on every query
+const listingsView = "property_listings"
+
+// listingsDefinition selects the properties as they are listed. Amenities are aggregated as the
+// text which is displayed, an array of their descriptions and a bitmask where the bit id - 1 is
+// set for each of them.
+func listingsDefinition(fullText bool) string {
+	tsv := ""
+	if fullText {
+		tsv = "p.description_tsv, "
+	}
+
+	return "select p.id, p.description, " + tsv +
+		"p.price, p.square_footage, p.rooms, p.bathrooms, p.latitude, p.longitude, " +
+		"p.lighting_id, l.description as lighting, " +
+		"coalesce(string_agg(am.description, ', ' order by am.id), '') as amenities, " +
+		"coalesce(array_agg(am.description order by am.id) filter (where am.id is not null), '{}') as amenity_list, " +
+		"coalesce(bit_or(1 << (am.id::int - 1)), 0) as amenity_mask, " +
+		"count(am.id) as amenity_count " +
+		"from properties p " +
+		"join lightings l on p.lighting_id = l.id " +
+		"left join properties_amenities pa on pa.property_id = p.id " +
+		"left join amenities am on pa.amenity_id = am.id " +
+		"group by p.id, l.description"
+}
+
+// listingsTable returns the table properties are listed from, aliased as p.
+func (r *Repository) listingsTable() string {
+	if r.hasListings {
+		return listingsView + " as p"
+	}
+
+	return fmt.Sprintf("(%s) as p", listingsDefinition(r.hasFullText))
+}
+
+// CreateListingsView creates the listings view from the current properties, replacing it if it
+// exists, along with its indexes.
+func (r *Repository) CreateListingsView(ctx context.Context) error {
+	fullText := r.hasFullText
+	statements := []string{
+		"drop materialized view if exists " + listingsView,
+		fmt.Sprintf("create materialized view %s as %s", listingsView, listingsDefinition(fullText)),
+		// Required to refresh the view concurrently
+		fmt.Sprintf("create unique index idx_%s_id on %s (id)", listingsView, listingsView),
+		// Used by the array operators of amenity filters
+		fmt.Sprintf("create index idx_%s_amenity_list on %s using gin (amenity_list)", listingsView, listingsView),
+		fmt.Sprintf("create index idx_%s_description_trgm on %s using gin (lower(description) gin_trgm_ops)", listingsView, listingsView),
+	}
+	if fullText {
+		statements = append(statements,
+			fmt.Sprintf("create index idx_%s_description_tsv on %s using gin (description_tsv)", listingsView, listingsView))
+	}
+
+	for _, statement := range statements {
+		if err := r.conn.WithContext(ctx).Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	r.hasListings = true
+
+	for _, index := range DefaultIndexes {
+		if err := r.CreateIndex(ctx, index); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RefreshListingsView updates the listings view with the current properties, without blocking
+// the queries reading it. The view is created if it doesn't exist.
+func (r *Repository) RefreshListingsView(ctx context.Context) error {
+	if !r.hasListingsView() {
+		return r.CreateListingsView(ctx)
+	}
+
+	return r.conn.WithContext(ctx).Exec("refresh materialized view concurrently " + listingsView).Error
+}
+
+func (r *Repository) hasListingsView() bool {
+	var count int64
+	r.conn.Raw("select count(*) from pg_matviews where matviewname = ?", listingsView).Scan(&count)
+
+	return count > 0
+}
@@ -4,6 +4,7 @@ Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
 package db
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/ta-ma/prop-filter-app/internal/datagen"
@@ -11,33 +12,68 @@ import (
 	"gorm.io/gorm"
 )
 
-func SeedDatabase(db *gorm.DB, entries uint) {
+// SeedDatabase recreates the tables and fills them with mock properties, keeping the indexes
+// they had. Seeding stops when the context is cancelled.
+func (r *Repository) SeedDatabase(ctx context.Context, entries uint) error {
+	conn := r.conn.WithContext(ctx)
+	indexes, err := r.indexDefinitions(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list the indexes: %w", err)
+	}
+	// The listings view and the full text search column are dropped along with the properties table
+	r.hasListings, r.hasFullText = false, false
+
 	// Migrate amenities
 	fmt.Println("DB: Migrating tables...")
-	deleteTable("properties_amenities")
-	migrateTable(&models.Amenity{})
+	if err := deleteTable(conn, "properties_amenities"); err != nil {
+		return fmt.Errorf("could not drop properties_amenities: %w", err)
+	}
+	if err := migrateTable(conn, &models.Amenity{}); err != nil {
+		return fmt.Errorf("could not migrate amenities: %w", err)
+	}
 
 	amenities := make([]models.Amenity, 0)
 	for _, a := range models.GetAmenityValues() {
 		amenities = append(amenities, models.Amenity{Description: a})
 	}
-	db.Create(&amenities)
+	if err := conn.Create(&amenities).Error; err != nil {
+		return fmt.Errorf("could not insert amenities: %w", err)
+	}
 
 	// Migrate lightings
-	migrateTable(&models.Lighting{})
+	if err := migrateTable(conn, &models.Lighting{}); err != nil {
+		return fmt.Errorf("could not migrate lightings: %w", err)
+	}
 
 	lightings := make([]models.Lighting, 0)
 	for _, l := range models.GetLightingValues() {
 		lightings = append(lightings, models.Lighting{Description: l})
 	}
-	db.Create(&lightings)
+	if err := conn.Create(&lightings).Error; err != nil {
+		return fmt.Errorf("could not insert lightings: %w", err)
+	}
 
 	// Migrate properties
-	migrateTable(&models.Property{})
+	if err := migrateTable(conn, &models.Property{}); err != nil {
+		return fmt.Errorf("could not migrate properties: %w", err)
+	}
+
+	// Full text search over descriptions
+	err = conn.Exec(`alter table properties add column description_tsv tsvector
+generated always as (to_tsvector('english', coalesce(description, ''))) stored`).Error
+	if err != nil {
+		return fmt.Errorf("could not add the full text search column: %w", err)
+	}
+	r.hasFullText = true
+
+	// Trigram similarity for fuzzy searches over descriptions
+	if err := conn.Exec("create extension if not exists pg_trgm").Error; err != nil {
+		return fmt.Errorf("could not create the pg_trgm extension: %w", err)
+	}
 
 	// Create haversine function
 	fmt.Println("DB: Creating functions...")
-	db.Exec(`create function fn_spheric_distance(x1 float, y1 float, x2 float, y2 float) returns float 
+	if err := conn.Exec(`create or replace function fn_spheric_distance(x1 float, y1 float, x2 float, y2 float) returns float 
 as
 $$
 declare 
@@ -53,7 +89,10 @@ begin
 return 2 * earth_radius_miles * ASIN(SQRT(hav_alpha));
 end;
 $$
-language plpgsql;`)
+language plpgsql;`).Error; err != nil {
+		return fmt.Errorf("could not create fn_spheric_distance: %w", err)
+	}
+
 	fmt.Println("DB: Generating mock data...")
 	props := datagen.GenerateMockProperties(entries)
 	// Batch insert in slices of 1000 elements due to Postgres restrictions
@@ -63,6 +102,10 @@ language plpgsql;`)
 	}
 
 	for i := uint(0); i < batches; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		lower := 1000 * i
 		upper := 1000 * (i + 1)
 
@@ -70,21 +113,39 @@ language plpgsql;`)
 			upper = entries
 		}
 		propsSlice := props[lower:upper]
-		db.Create(&propsSlice)
+		if err := conn.Create(&propsSlice).Error; err != nil {
+			return fmt.Errorf("could not insert properties: %w", err)
+		}
+	}
+
+	fmt.Println("DB: Creating listings view...")
+	if err := r.CreateListingsView(ctx); err != nil {
+		return fmt.Errorf("could not create the listings view: %w", err)
+	}
+	if err := r.restoreIndexes(ctx, indexes); err != nil {
+		return fmt.Errorf("could not recreate the indexes: %w", err)
 	}
 	fmt.Println("DB: Seeding finished.")
+
+	return nil
 }
 
-func migrateTable[T any](model *T) {
-	if db.Migrator().HasTable(model) {
-		db.Migrator().DropTable(model)
+func migrateTable[T any](conn *gorm.DB, model *T) error {
+	migrator := conn.Migrator()
+	if migrator.HasTable(model) {
+		if err := migrator.DropTable(model); err != nil {
+			return err
+		}
 	}
 
-	db.AutoMigrate(model)
+	return migrator.AutoMigrate(model)
 }
 
-func deleteTable(tableName string) {
-	if db.Migrator().HasTable(tableName) {
-		db.Migrator().DropTable(tableName)
+func deleteTable(conn *gorm.DB, tableName string) error {
+	migrator := conn.Migrator()
+	if migrator.HasTable(tableName) {
+		return migrator.DropTable(tableName)
 	}
+
+	return nil
 }
@@ -0,0 +1,147 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/ta-ma/prop-filter-app/internal/columns"
+	"github.com/ta-ma/prop-filter-app/internal/models"
+)
+
+type Format int
+
+const (
+	CSV Format = iota
+	JSON
+)
+
+func (f Format) Extension() string {
+	if f == JSON {
+		return "json"
+	}
+
+	return "csv"
+}
+
+// WriteFile creates the file at the given path and writes the properties to it.
+func WriteFile(path string, format Format, props []models.PropertyViewModel, cols []columns.Column) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if format == JSON {
+		err = WriteJSON(file, props, cols)
+	} else {
+		err = WriteCSV(file, props, cols)
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// WriteCSV writes the ID and the given columns of each property, with a header row
+// containing the column keys.
+func WriteCSV(w io.Writer, props []models.PropertyViewModel, cols []columns.Column) error {
+	writer := csv.NewWriter(w)
+
+	cols = exportedColumns(cols)
+	header := []string{"id"}
+	for _, c := range cols {
+		header = append(header, c.Key)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, p := range props {
+		record := []string{strconv.FormatUint(uint64(p.ID), 10)}
+		for _, c := range cols {
+			switch v := value(p, c).(type) {
+			case nil:
+				record = append(record, "")
+			case json.Number:
+				record = append(record, v.String())
+			default:
+				record = append(record, fmt.Sprint(v))
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes an array with an object for each property, containing its ID and
+// the given columns keyed by their column key.
+func WriteJSON(w io.Writer, props []models.PropertyViewModel, cols []columns.Column) error {
+	cols = exportedColumns(cols)
+	records := make([]map[string]any, 0, len(props))
+	for _, p := range props {
+		record := map[string]any{"id": p.ID}
+		for _, c := range cols {
+			record[c.Key] = value(p, c)
+		}
+		records = append(records, record)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(records); err != nil {
+		return fmt.Errorf("properties could not be encoded: %w", err)
+	}
+
+	return nil
+}
+
+// exportedColumns replaces the columns made of several numbers, such as the location, with them.
+func exportedColumns(cols []columns.Column) []columns.Column {
+	var result []columns.Column
+	for _, c := range cols {
+		if len(c.Parts) > 0 {
+			result = append(result, c.Parts...)
+		} else {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+// value returns the value of a column to be exported. Numerical columns give their raw number,
+// or nil if it is undefined, and the rest give the text they display.
+func value(p models.PropertyViewModel, c columns.Column) any {
+	// The value of text columns ranks them, such as the lighting level
+	if c.Value == nil || c.TextSql {
+		return c.Format(p)
+	}
+
+	v := c.Value(p)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return json.Number(formatNumber(v))
+}
+
+// formatNumber returns the shortest representation of a number. Values which fit in a float32,
+// like those of the float32 fields, are formatted as such so 1500.3 isn't 1500.300048828125.
+func formatNumber(v float64) string {
+	if float64(float32(v)) == v {
+		return strconv.FormatFloat(v, 'f', -1, 32)
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package export
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ta-ma/prop-filter-app/internal/columns"
+	"github.com/ta-ma/prop-filter-app/internal/models"
+)
+
+func TestWrite(t *testing.T) {
+	props := []models.PropertyViewModel{
+		{ID: 7, Description: "Main St, Boston", Price: 1500.3, Rooms: 3, Lighting: "high", Latitude: 40.7128, Longitude: -74.006},
+		{ID: 12, Description: `Elm "North" St`, Price: 900, Rooms: 1, Lighting: "low", Latitude: -33.5},
+	}
+	cols, err := columns.Parse("description,price,rooms,lighting,location", false, false)
+	assert.NoError(t, err)
+
+	var csvOutput bytes.Buffer
+	assert.NoError(t, WriteCSV(&csvOutput, props, cols))
+	assert.Equal(t, "id,description,price,rooms,lighting,latitude,longitude\n"+
+		"7,\"Main St, Boston\",1500.3,3,high,40.7128,-74.006\n"+
+		"12,\"Elm \"\"North\"\" St\",900,1,low,-33.5,0\n", csvOutput.String())
+
+	var jsonOutput bytes.Buffer
+	assert.NoError(t, WriteJSON(&jsonOutput, props, cols))
+	assert.JSONEq(t, `[
+		{"id": 7, "description": "Main St, Boston", "price": 1500.3, "rooms": 3, "lighting": "high", "latitude": 40.7128, "longitude": -74.006},
+		{"id": 12, "description": "Elm \"North\" St", "price": 900, "rooms": 1, "lighting": "low", "latitude": -33.5, "longitude": 0}
+	]`, jsonOutput.String())
+}
+
+func TestWriteUndefinedValues(t *testing.T) {
+	props := []models.PropertyViewModel{{ID: 3, Price: 1000, Rooms: 2, Bathrooms: 1}}
+	cols := []columns.Column{{Key: "ratio", Format: func(models.PropertyViewModel) string { return "-" },
+		Value: func(models.PropertyViewModel) float64 { return math.NaN() }}}
+
+	var csvOutput bytes.Buffer
+	assert.NoError(t, WriteCSV(&csvOutput, props, cols))
+	assert.Equal(t, "id,ratio\n3,\n", csvOutput.String())
+
+	var jsonOutput bytes.Buffer
+	assert.NoError(t, WriteJSON(&jsonOutput, props, cols))
+	assert.JSONEq(t, `[{"id": 3, "ratio": null}]`, jsonOutput.String())
+}
@@ -0,0 +1,239 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package filter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Formula is an arithmetic expression over numerical fields, such as "price * 0.0055 + 150".
+// Only numbers, the fields it was parsed with, the +, -, * and / operators and parentheses
+// are allowed, so it can be safely compiled to SQL.
+type Formula struct {
+	root   formulaNode
+	fields map[string]string
+}
+
+type formulaNode struct {
+	operator    byte // 0 for numbers and fields, 'n' for negations
+	value       string
+	field       bool
+	left, right *formulaNode
+}
+
+type formulaToken struct {
+	text string
+	pos  int
+}
+
+// ParseFormula parses a formula which can use the given fields, mapped to their SQL expression.
+func ParseFormula(formula string, fields map[string]string) (*Formula, error) {
+	tokens, err := tokenizeFormula(formula)
+	if err != nil {
+		return nil, err
+	}
+
+	p := formulaParser{tokens: tokens, fields: fields, formula: formula}
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.current < len(p.tokens) {
+		return nil, p.errorf(`unexpected "%s"`, p.tokens[p.current].text)
+	}
+
+	return &Formula{root: *root, fields: fields}, nil
+}
+
+// Sql returns the SQL expression of the formula. Divisions by zero result in null.
+func (f *Formula) Sql() string {
+	return f.root.sql(f.fields)
+}
+
+// Eval calculates the formula using the values of the fields. Divisions by zero result in NaN.
+func (f *Formula) Eval(value func(field string) float64) float64 {
+	return f.root.eval(value)
+}
+
+func (n formulaNode) sql(fields map[string]string) string {
+	switch n.operator {
+	case 0:
+		if n.field {
+			return fields[n.value]
+		}
+		return n.value
+	case '/':
+		return fmt.Sprintf("(%s::float / nullif(%s, 0))", n.left.sql(fields), n.right.sql(fields))
+	case 'n':
+		return fmt.Sprintf("(-%s)", n.left.sql(fields))
+	}
+
+	return fmt.Sprintf("(%s %c %s)", n.left.sql(fields), n.operator, n.right.sql(fields))
+}
+
+func (n formulaNode) eval(value func(string) float64) float64 {
+	switch n.operator {
+	case 0:
+		if n.field {
+			return value(n.value)
+		}
+		number, _ := strconv.ParseFloat(n.value, 64)
+		return number
+	case 'n':
+		return -n.left.eval(value)
+	}
+
+	left, right := n.left.eval(value), n.right.eval(value)
+	switch n.operator {
+	case '+':
+		return left + right
+	case '-':
+		return left - right
+	case '*':
+		return left * right
+	}
+
+	if right == 0 {
+		return math.NaN()
+	}
+	return left / right
+}
+
+func tokenizeFormula(formula string) ([]formulaToken, error) {
+	var tokens []formulaToken
+	runes := []rune(formula)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case strings.ContainsRune("+-*/()", r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+		default:
+			return nil, fmt.Errorf(`formula "%s" has an invalid character "%c" at position %d`, formula, r, i+1)
+		}
+
+		tokens = append(tokens, formulaToken{text: string(runes[start:i]), pos: start + 1})
+	}
+
+	return tokens, nil
+}
+
+// formulaParser is a recursive descent parser of formulas, where sums have the lowest
+// precedence, followed by products and then signs, numbers, fields and parentheses.
+type formulaParser struct {
+	tokens  []formulaToken
+	current int
+	fields  map[string]string
+	formula string
+}
+
+func (p *formulaParser) errorf(format string, args ...any) error {
+	pos := len([]rune(p.formula)) + 1
+	if p.current < len(p.tokens) {
+		pos = p.tokens[p.current].pos
+	}
+
+	return fmt.Errorf(`formula "%s" is not valid at position %d: %s`, p.formula, pos, fmt.Sprintf(format, args...))
+}
+
+func (p *formulaParser) next(operators string) (byte, bool) {
+	if p.current >= len(p.tokens) {
+		return 0, false
+	}
+
+	text := p.tokens[p.current].text
+	if len(text) != 1 || !strings.Contains(operators, text) {
+		return 0, false
+	}
+	p.current++
+	return text[0], true
+}
+
+func (p *formulaParser) parseSum() (*formulaNode, error) {
+	return p.parseBinary("+-", p.parseProduct)
+}
+
+func (p *formulaParser) parseProduct() (*formulaNode, error) {
+	return p.parseBinary("*/", p.parseFactor)
+}
+
+func (p *formulaParser) parseBinary(operators string, operand func() (*formulaNode, error)) (*formulaNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		operator, ok := p.next(operators)
+		if !ok {
+			return left, nil
+		}
+
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &formulaNode{operator: operator, left: left, right: right}
+	}
+}
+
+func (p *formulaParser) parseFactor() (*formulaNode, error) {
+	if operator, ok := p.next("+-"); ok {
+		operand, err := p.parseFactor()
+		if err != nil || operator == '+' {
+			return operand, err
+		}
+		return &formulaNode{operator: 'n', left: operand}, nil
+	}
+
+	if _, ok := p.next("("); ok {
+		node, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.next(")"); !ok {
+			return nil, p.errorf(`")" expected`)
+		}
+		return node, nil
+	}
+
+	if p.current >= len(p.tokens) {
+		return nil, p.errorf("a number or field expected")
+	}
+
+	text := p.tokens[p.current].text
+	field := false
+	switch {
+	case plainNumberRegex.MatchString(text):
+		if strings.HasPrefix(text, ".") {
+			text = "0" + text
+		}
+	case unicode.IsLetter(rune(text[0])) || text[0] == '_':
+		if _, ok := p.fields[text]; !ok {
+			return nil, p.errorf(`unknown field "%s"`, text)
+		}
+		field = true
+	default:
+		return nil, p.errorf(`unexpected "%s"`, text)
+	}
+
+	p.current++
+	return &formulaNode{value: text, field: field}, nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package filter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormula(t *testing.T) {
+	type testCase struct {
+		formula      string
+		expectedSql  string
+		expectedEval float64
+		errExpected  bool
+	}
+
+	fields := map[string]string{"price": "p.price", "rooms": "p.rooms", "bathrooms": "p.bathrooms"}
+	values := map[string]float64{"price": 200000, "rooms": 3, "bathrooms": 0}
+
+	testCases := []testCase{
+		{formula: "price * 0.0055 + 150", expectedSql: "((p.price * 0.0055) + 150)", expectedEval: 1250},
+		{formula: "-(rooms + 1) * 2", expectedSql: "((-(p.rooms + 1)) * 2)", expectedEval: -8},
+		{formula: "rooms - .5 - 1", expectedSql: "((p.rooms - 0.5) - 1)", expectedEval: 1.5},
+		{formula: "rooms / bathrooms", expectedSql: "(p.rooms::float / nullif(p.bathrooms, 0))", expectedEval: math.NaN()},
+		{formula: "price; drop table properties", errExpected: true},
+		{formula: "price * tax", errExpected: true},
+		{formula: "(price + 1", errExpected: true},
+		{formula: "price 2", errExpected: true},
+		{formula: "1.2.3", errExpected: true},
+		{formula: "", errExpected: true},
+	}
+
+	for _, test := range testCases {
+		formula, err := ParseFormula(test.formula, fields)
+
+		if test.errExpected {
+			assert.Error(t, err, test.formula)
+			continue
+		}
+		assert.NoError(t, err, test.formula)
+		assert.Equal(t, test.expectedSql, formula.Sql())
+
+		actual := formula.Eval(func(field string) float64 { return values[field] })
+		if math.IsNaN(test.expectedEval) {
+			assert.True(t, math.IsNaN(actual))
+		} else {
+			assert.InDelta(t, test.expectedEval, actual, 1e-9)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type valueKind int
+
+const (
+	textValue valueKind = iota
+	numberValue
+	numberListValue
+	wordValue
+	wordListValue
+	integerValue
+)
+
+// operatorRule is a group of operators which take the same kind of value.
+type operatorRule struct {
+	operators []string
+	kind      valueKind
+	// Allowed values of words and lists of words
+	words []string
+	// Characters allowed in text values besides letters, digits, "_", spaces and ","
+	extraChars string
+}
+
+// grammar describes the expressions allowed for a type of field.
+type grammar struct {
+	rules []operatorRule
+	// Inclusive ranges of numbers, such as "3..5", are allowed without an operator
+	ranges bool
+}
+
+var lightingValues = []string{"low", "medium", "high"}
+var amenityValues = []string{"yard", "pool", "garage", "rooftop", "waterfront"}
+
+var strGrammar = grammar{rules: []operatorRule{
+	{operators: []string{"=", "!=", "has:", "!has:", "in:", "!in:", "~"}, kind: textValue},
+}}
+
+var fullTextGrammar = grammar{rules: []operatorRule{
+	{operators: []string{"=", "!=", "has:", "!has:", "in:", "!in:", "~", "match:"}, kind: textValue, extraChars: `"-`},
+}}
+
+var numGrammar = grammar{ranges: true, rules: []operatorRule{
+	{operators: []string{"<", ">", "=", ">=", "<=", "!="}, kind: numberValue},
+	{operators: []string{"in:", "!in:"}, kind: numberListValue},
+}}
+
+var lightingGrammar = grammar{rules: []operatorRule{
+	{operators: []string{"=", "!="}, kind: wordValue, words: lightingValues},
+	{operators: []string{"in:", "!in:"}, kind: wordListValue, words: lightingValues},
+}}
+
+var amenityGrammar = grammar{rules: []operatorRule{
+	{operators: []string{"=", "!=", "has:", "!has:"}, kind: wordValue, words: amenityValues},
+	{operators: []string{"all:", "any:", "none:", "exactly:", "in:", "!in:"}, kind: wordListValue, words: amenityValues},
+	{operators: []string{"count<", "count>", "count=", "count>=", "count<="}, kind: integerValue},
+}}
+
+// ExprError describes why a filter expression is not valid.
+type ExprError struct {
+	Expr string
+	// Position of the error in Expr, starting at 1
+	Pos      int
+	Message  string
+	Expected []string
+	// Closest valid token to the one which was written, if any
+	Suggestion string
+	// What the expression filters, such as the "--price" flag, if known
+	Source string
+}
+
+func (e *ExprError) Error() string {
+	message := fmt.Sprintf(`filter expression "%s" is not valid at position %d: %s`, e.Expr, e.Pos, e.Message)
+	if e.Source != "" {
+		message = e.Source + ": " + message
+	}
+
+	lines := []string{
+		message,
+		"  " + e.Expr,
+		"  " + strings.Repeat(" ", e.Pos-1) + "^",
+	}
+	if len(e.Expected) > 0 {
+		lines = append(lines, "expected "+quoteList(e.Expected))
+	}
+	if e.Suggestion != "" {
+		lines = append(lines, fmt.Sprintf(`did you mean "%s"?`, e.Suggestion))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// exprLexer splits a filter expression in operators and values, validating them with a grammar.
+type exprLexer struct {
+	expr    string
+	grammar grammar
+}
+
+func (l exprLexer) errorAt(offset int, message string, expected []string, written string) *ExprError {
+	return &ExprError{
+		Expr:       l.expr,
+		Pos:        utf8.RuneCountInString(l.expr[:offset]) + 1,
+		Message:    message,
+		Expected:   expected,
+		Suggestion: suggest(written, expected),
+	}
+}
+
+func (l exprLexer) split() ([]filterExpr, error) {
+	expressions := make([]filterExpr, 0)
+	offset := 0
+
+	for _, part := range strings.Split(l.expr, Separator) {
+		parsed, err := l.parsePart(part, offset)
+		if err != nil {
+			return []filterExpr{}, err
+		}
+
+		expressions = append(expressions, parsed...)
+		offset += len(part) + len(Separator)
+	}
+
+	return expressions, nil
+}
+
+func (l exprLexer) operators() []string {
+	var operators []string
+	for _, rule := range l.grammar.rules {
+		operators = append(operators, rule.operators...)
+	}
+	if l.grammar.ranges {
+		operators = append(operators, "<min>..<max>")
+	}
+
+	return operators
+}
+
+// parsePart parses one of the expressions separated by ";", found at the given offset.
+func (l exprLexer) parsePart(part string, offset int) ([]filterExpr, error) {
+	if part == "" {
+		return nil, l.errorAt(offset, "empty expression", l.operators(), "")
+	}
+
+	var rule operatorRule
+	operator := ""
+	for _, r := range l.grammar.rules {
+		for _, o := range r.operators {
+			if strings.HasPrefix(part, o) && len(o) > len(operator) {
+				rule, operator = r, o
+			}
+		}
+	}
+
+	if operator == "" && l.grammar.ranges && strings.Contains(part, RangeSeparator) {
+		return l.parseRange(part, offset)
+	}
+
+	// Operators such as "=>" start with a valid one, but the whole operator is not
+	if written := leadingOperator(part); operator == "" || len(written) > len(operator) {
+		return nil, l.errorAt(offset, fmt.Sprintf(`unknown operator "%s"`, written), l.operators(), written)
+	}
+
+	value, err := l.parseValue(rule, part[len(operator):], offset+len(operator))
+	if err != nil {
+		return nil, err
+	}
+
+	return []filterExpr{{Operator: operator, Value: value}}, nil
+}
+
+// parseValue validates the value of an operator and returns it normalized.
+func (l exprLexer) parseValue(rule operatorRule, value string, offset int) (string, error) {
+	switch rule.kind {
+	case numberValue:
+		return l.parseNumber(value, offset)
+	case integerValue:
+		if value == "" || strings.Trim(value, "0123456789") != "" {
+			return "", l.errorAt(offset, fmt.Sprintf(`"%s" is not a whole number`, value), nil, "")
+		}
+		return value, nil
+	case wordValue:
+		return value, l.parseWord(rule, value, offset)
+	case numberListValue, wordListValue:
+		items := strings.Split(value, ",")
+		for i, item := range items {
+			var err error
+			if rule.kind == numberListValue {
+				// Commas separate the values, so they can't be thousands separators
+				if err = checkListItem(item); err != nil {
+					return "", l.errorAt(offset, err.Error(), nil, "")
+				}
+				items[i], err = l.parseNumber(item, offset)
+			} else {
+				err = l.parseWord(rule, item, offset)
+			}
+			if err != nil {
+				return "", err
+			}
+			offset += len(item) + 1
+		}
+		return strings.Join(items, ","), nil
+	}
+
+	if value == "" {
+		return "", l.errorAt(offset, "a value is required", nil, "")
+	}
+	for i, r := range value {
+		if !isTextChar(r) && !strings.ContainsRune(rule.extraChars, r) {
+			return "", l.errorAt(offset+i, fmt.Sprintf(`character "%c" is not allowed in text values`, r), nil, "")
+		}
+	}
+	return value, nil
+}
+
+func (l exprLexer) parseNumber(value string, offset int) (string, error) {
+	number, err := parseNumber(value)
+	if err != nil {
+		return "", l.errorAt(offset, err.Error(), nil, "")
+	}
+
+	return number, nil
+}
+
+func (l exprLexer) parseWord(rule operatorRule, value string, offset int) error {
+	for _, w := range rule.words {
+		if value == w {
+			return nil
+		}
+	}
+
+	if value == "" {
+		return l.errorAt(offset, "a value is required", rule.words, "")
+	}
+	return l.errorAt(offset, fmt.Sprintf(`unknown value "%s"`, value), rule.words, value)
+}
+
+// parseRange rewrites an inclusive range to a pair of expressions. Either of its ends can be
+// omitted to leave it open.
+func (l exprLexer) parseRange(part string, offset int) ([]filterExpr, error) {
+	lowerValue, upperValue, _ := strings.Cut(part, RangeSeparator)
+	if strings.Contains(upperValue, RangeSeparator) || (lowerValue == "" && upperValue == "") {
+		return nil, l.errorAt(offset, fmt.Sprintf(`range "%s" must have the format "<min>..<max>"`, part), nil, "")
+	}
+
+	var expressions []filterExpr
+	var lower, upper string
+	var err error
+	if lowerValue != "" {
+		if lower, err = l.parseNumber(lowerValue, offset); err != nil {
+			return nil, err
+		}
+		expressions = append(expressions, filterExpr{Operator: ">=", Value: lower})
+	}
+	if upperValue != "" {
+		if upper, err = l.parseNumber(upperValue, offset+len(lowerValue)+len(RangeSeparator)); err != nil {
+			return nil, err
+		}
+		expressions = append(expressions, filterExpr{Operator: "<=", Value: upper})
+	}
+
+	if lower != "" && upper != "" && compareNumbers(lower, upper) > 0 {
+		return nil, l.errorAt(offset, fmt.Sprintf(`range "%s" starts after it ends`, part), nil, "")
+	}
+
+	return expressions, nil
+}
+
+func isTextChar(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' ||
+		r == ',' || r == ' ' || r == '\t'
+}
+
+// leadingOperator returns the start of an expression which looks like its operator: a word
+// up to a ":" or a sequence of symbols.
+func leadingOperator(part string) string {
+	end := strings.IndexFunc(part, func(r rune) bool {
+		return !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '!')
+	})
+	switch {
+	case end == -1:
+		return part
+	case end > 0 && part[end] == ':':
+		return part[:end+1]
+	case end > 0:
+		return part[:end]
+	}
+
+	end = strings.IndexFunc(part, func(r rune) bool { return !strings.ContainsRune("<>=!~", r) })
+	if end == -1 {
+		return part
+	}
+	return part[:max(end, 1)]
+}
+
+// suggest returns the option closest to the written token, if it is similar enough.
+func suggest(written string, options []string) string {
+	if written == "" {
+		return ""
+	}
+
+	best, bestDistance := "", -1
+	for _, o := range options {
+		d := editDistance(strings.ToLower(written), o)
+		// On ties, options as long as the written token are more likely, such as ">=" for "=>"
+		if bestDistance == -1 || d < bestDistance || (d == bestDistance && len(o) == len(written) && len(best) != len(written)) {
+			best, bestDistance = o, d
+		}
+	}
+
+	if bestDistance > max(utf8.RuneCountInString(written)/3, 1) {
+		return ""
+	}
+	return best
+}
+
+// editDistance returns the optimal string alignment distance between two strings: the number
+// of insertions, deletions, substitutions and transpositions needed to turn one into the other.
+func editDistance(a string, b string) int {
+	x, y := []rune(a), []rune(b)
+	d := make([][]int, len(x)+1)
+	for i := range d {
+		d[i] = make([]int, len(y)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && x[i-1] == y[j-2] && x[i-2] == y[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(x)][len(y)]
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf(`"%s"`, v)
+	}
+
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return "one of " + strings.Join(quoted, ", ")
+}
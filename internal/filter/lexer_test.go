@@ -0,0 +1,54 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExprError(t *testing.T) {
+	type testCase struct {
+		expr               string
+		g                  grammar
+		expectedPos        int
+		expectedSuggestion string
+	}
+
+	testCases := []testCase{
+		{expr: "has;yard", g: strGrammar, expectedPos: 1, expectedSuggestion: "has:"},
+		{expr: "has:yard;hsa:pool", g: strGrammar, expectedPos: 10, expectedSuggestion: "has:"},
+		{expr: "has::yard", g: strGrammar, expectedPos: 5, expectedSuggestion: ""},
+		{expr: "has:yard;", g: strGrammar, expectedPos: 10, expectedSuggestion: ""},
+		{expr: "=>5", g: numGrammar, expectedPos: 1, expectedSuggestion: ">="},
+		{expr: ">1;in:2,x", g: numGrammar, expectedPos: 9, expectedSuggestion: ""},
+		{expr: "all:pool,gargae", g: amenityGrammar, expectedPos: 10, expectedSuggestion: "garage"},
+		{expr: "=Yard", g: amenityGrammar, expectedPos: 2, expectedSuggestion: "yard"},
+		{expr: "count>=many", g: amenityGrammar, expectedPos: 8, expectedSuggestion: ""},
+		{expr: "=hihg", g: lightingGrammar, expectedPos: 2, expectedSuggestion: "high"},
+		{expr: "=dim", g: lightingGrammar, expectedPos: 2, expectedSuggestion: ""},
+	}
+
+	for _, test := range testCases {
+		_, err := splitExpr(test.expr, test.g)
+
+		var exprErr *ExprError
+		if assert.ErrorAs(t, err, &exprErr, test.expr) {
+			assert.Equal(t, test.expectedPos, exprErr.Pos, test.expr)
+			assert.Equal(t, test.expectedSuggestion, exprErr.Suggestion, test.expr)
+		}
+	}
+}
+
+func TestExprErrorMessage(t *testing.T) {
+	_, err := splitExpr("has:pool;hsa:yard", strGrammar)
+
+	expected := `filter expression "has:pool;hsa:yard" is not valid at position 10: unknown operator "hsa:"
+  has:pool;hsa:yard
+           ^
+expected one of "=", "!=", "has:", "!has:", "in:", "!in:", "~"
+did you mean "has:"?`
+	assert.EqualError(t, err, expected)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const RangeSeparator = ".."
+
+var groupedDigitsRegex = regexp.MustCompile(`^[0-9]{1,3}(?:,[0-9]{3})+(?:\.[0-9]+)?$`)
+var plainNumberRegex = regexp.MustCompile(`^(?:[0-9]+(?:\.[0-9]+)?|\.[0-9]+)$`)
+
+// A group of 3 digits with leading zeros, which follows a thousands separator rather than a
+// separator of list values
+var zeroLedGroupRegex = regexp.MustCompile(`^0[0-9]{2}(?:\.[0-9]+)?$`)
+
+var currencySymbols = []string{"$", "€", "£"}
+
+// parseNumber returns the plain representation of a number which can have a sign, a currency
+// symbol, thousands separators and a "k" or "m" suffix.
+func parseNumber(value string) (string, error) {
+	number := strings.TrimSpace(value)
+	if number == "" {
+		return "", fmt.Errorf("a number is required")
+	}
+
+	sign := ""
+	if number[0] == '+' || number[0] == '-' {
+		sign, number = number[:1], number[1:]
+	}
+	for _, symbol := range currencySymbols {
+		number = strings.TrimPrefix(number, symbol)
+	}
+	if sign == "" && number != "" && (number[0] == '+' || number[0] == '-') {
+		sign, number = number[:1], number[1:]
+	}
+
+	shift := 0
+	switch {
+	case strings.HasSuffix(number, "k") || strings.HasSuffix(number, "K"):
+		shift, number = 3, number[:len(number)-1]
+	case strings.HasSuffix(number, "m") || strings.HasSuffix(number, "M"):
+		shift, number = 6, number[:len(number)-1]
+	}
+
+	if strings.Contains(number, ",") {
+		if !groupedDigitsRegex.MatchString(number) {
+			return "", fmt.Errorf(`"%s" is ambiguous, thousands separators must group 3 digits and "." is the decimal separator`, value)
+		}
+		number = strings.ReplaceAll(number, ",", "")
+	}
+
+	if !plainNumberRegex.MatchString(number) {
+		return "", fmt.Errorf(`"%s" is not a valid number`, value)
+	}
+	if strings.HasPrefix(number, ".") {
+		number = "0" + number
+	}
+
+	if shift > 0 {
+		number = shiftDecimal(number, shift)
+	} else if sign == "+" {
+		// Keep the value as it was written
+		return "+" + number, nil
+	}
+
+	if sign == "-" {
+		return "-" + number, nil
+	}
+	return number, nil
+}
+
+// checkListItem rejects the values of a list which suggest its commas were meant as thousands
+// separators, such as "$1,200" or "1,050".
+func checkListItem(item string) error {
+	for _, symbol := range currencySymbols {
+		if strings.Contains(item, symbol) {
+			return fmt.Errorf(`"%s" is ambiguous, "," separates the values of lists so currency symbols and thousands separators are not allowed in them`, item)
+		}
+	}
+	if zeroLedGroupRegex.MatchString(strings.TrimSpace(item)) {
+		return fmt.Errorf(`"%s" looks like a group of thousands, "," separates the values of lists so numbers must be written without thousands separators`, item)
+	}
+
+	return nil
+}
+
+// shiftDecimal multiplies a plain number by a power of 10 without the rounding errors of floats.
+func shiftDecimal(number string, places int) string {
+	integer, fraction, _ := strings.Cut(number, ".")
+	fraction += strings.Repeat("0", max(places-len(fraction), 0))
+	integer, fraction = integer+fraction[:places], fraction[places:]
+
+	integer = strings.TrimLeft(integer, "0")
+	if integer == "" {
+		integer = "0"
+	}
+	fraction = strings.TrimRight(fraction, "0")
+	if fraction == "" {
+		return integer
+	}
+	return integer + "." + fraction
+}
+
+// compareNumbers compares two plain numbers, returning a negative number if a < b, 0 if they
+// are equal and a positive number if a > b.
+func compareNumbers(a string, b string) int {
+	var x, y float64
+	fmt.Sscan(a, &x)
+	fmt.Sscan(b, &y)
+
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,56 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitNumExpr(t *testing.T) {
+	type testCase struct {
+		expr        string
+		expected    []filterExpr
+		errExpected bool
+	}
+
+	testCases := []testCase{
+		{expr: ">=250000.0;<=475000.0",
+			expected: []filterExpr{{Operator: ">=", Value: "250000.0"}, {Operator: "<=", Value: "475000.0"}}},
+		{expr: "250k..475k", expected: []filterExpr{{Operator: ">=", Value: "250000"}, {Operator: "<=", Value: "475000"}}},
+		{expr: "3..5", expected: []filterExpr{{Operator: ">=", Value: "3"}, {Operator: "<=", Value: "5"}}},
+		{expr: "..1.5m", expected: []filterExpr{{Operator: "<=", Value: "1500000"}}},
+		{expr: "2..", expected: []filterExpr{{Operator: ">=", Value: "2"}}},
+		{expr: "<$1,200,000", expected: []filterExpr{{Operator: "<", Value: "1200000"}}},
+		{expr: ">-$1,000.50", expected: []filterExpr{{Operator: ">", Value: "-1000.50"}}},
+		{expr: "=0.0125K", expected: []filterExpr{{Operator: "=", Value: "12.5"}}},
+		{expr: ">.5", expected: []filterExpr{{Operator: ">", Value: "0.5"}}},
+		{expr: "in:100k,1m", expected: []filterExpr{{Operator: "in:", Value: "100000,1000000"}}},
+		{expr: "in:1,200", expected: []filterExpr{{Operator: "in:", Value: "1,200"}}},
+		{expr: "in:$1,200", errExpected: true},
+		{expr: "in:1,€200", errExpected: true},
+		{expr: "!in:2,050", errExpected: true},
+		{expr: "in:1,050.5", errExpected: true},
+		{expr: "<abc", errExpected: true},
+		{expr: "<1,20", errExpected: true},
+		{expr: "<1.200,50", errExpected: true},
+		{expr: "<1.2.3", errExpected: true},
+		{expr: "5..3", errExpected: true},
+		{expr: "..", errExpected: true},
+		{expr: "1..2..3", errExpected: true},
+		{expr: "<", errExpected: true},
+	}
+
+	for _, test := range testCases {
+		actual, err := splitExpr(test.expr, numGrammar)
+
+		if test.errExpected {
+			assert.Error(t, err, test.expr)
+			continue
+		}
+		assert.NoError(t, err, test.expr)
+		assert.Equal(t, test.expected, actual)
+	}
+}
@@ -16,12 +16,9 @@ const (
 	Num
 	Lighting
 	Amenity
+	FullText
 )
 
-const StrRegex = `^(=|has:)((?:\w|\s|,)+)$`
-const NumRegex = `^(<|>|=|>=|<=)([+-]?(?:[0-9]+[.])?[0-9]+)$`
-const LightingRegex = `^(=)(low|medium|high)$`
-const AmenityRegex = `^(=|has:)(yard|pool|garage|rooftop|waterfront)$`
 const DistanceRegex = `^distance\(([+-]?(?:[0-9]+[.])?[0-9]+),([+-]?(?:[0-9]+[.])?[0-9]+)\)(?:(<|>|=|>=|<=)([+-]?(?:[0-9]+[.])?[0-9]+))?$`
 const Separator = ";"
 
@@ -36,14 +33,40 @@ type DistanceFilterData struct {
 	Sql string
 }
 
+// FilterNode is the parsed expression used to filter a field.
+type FilterNode struct {
+	Field string
+	Expr  string
+	Parts []FilterPart
+	Sql   string
+}
+
+// FilterPart is one of the operators and values of an expression, along with its translation.
+type FilterPart struct {
+	Operator string
+	Value    string
+	Sql      string
+}
+
 type Translator struct {
 	Translations []string
 	Err          error
+	// Parsed expressions, in the same order as the translations
+	Tree []FilterNode
+	// If false, full text expressions are matched word by word instead
+	FullTextSearch bool
+	// Relevance of each full text expression, used to sort the results
+	Ranks []string
+	// Similarity of each fuzzy expression to the field
+	Scores []string
 }
 
 func (translator *Translator) Init() {
 	translator.Translations = make([]string, 0)
 	translator.Err = nil
+	translator.Tree = make([]FilterNode, 0)
+	translator.Ranks = make([]string, 0)
+	translator.Scores = make([]string, 0)
 }
 
 func (translator *Translator) Translate(field string, expr string, exprType ExprType) {
@@ -51,12 +74,52 @@ func (translator *Translator) Translate(field string, expr string, exprType Expr
 		return
 	}
 
-	var t string
-	t, translator.Err = TranslateToSql(field, expr, exprType)
+	g, translatorFunc := getGrammar(exprType)
+	if exprType == FullText && translator.FullTextSearch {
+		translatorFunc = translator.translateFullTextExpr
+	}
+
+	var node FilterNode
+	node, translator.Err = translateFilterExpr(field, expr, g, translator.withScores(translatorFunc))
 	if translator.Err != nil {
 		return
 	}
-	translator.Translations = append(translator.Translations, t)
+	translator.Translations = append(translator.Translations, node.Sql)
+	translator.Tree = append(translator.Tree, node)
+}
+
+// GetRankOrder returns the SQL expression to sort the results by the relevance of the full
+// text expressions, most relevant first. It is empty if there are none.
+func (translator *Translator) GetRankOrder() string {
+	if len(translator.Ranks) == 0 {
+		return ""
+	}
+
+	return strings.Join(translator.Ranks, " + ") + " desc"
+}
+
+// GetSimilarityScore returns the SQL expression of the similarity between the fields and the
+// values of the fuzzy expressions, taking the best one if there are many. It is empty if
+// there are none.
+func (translator *Translator) GetSimilarityScore() string {
+	switch len(translator.Scores) {
+	case 0:
+		return ""
+	case 1:
+		return translator.Scores[0]
+	}
+
+	return fmt.Sprintf("greatest(%s)", strings.Join(translator.Scores, ", "))
+}
+
+// withScores records the similarity score of the fuzzy expressions translated by the function.
+func (translator *Translator) withScores(translatorFunc func(string, filterExpr) string) func(string, filterExpr) string {
+	return func(field string, e filterExpr) string {
+		if e.Operator == "~" {
+			translator.Scores = append(translator.Scores, fmt.Sprintf("word_similarity(lower('%s'), lower(%s))", e.Value, field))
+		}
+		return translatorFunc(field, e)
+	}
 }
 
 func (translator *Translator) GetSqlTranslation() string {
@@ -83,75 +146,219 @@ func (translator *Translator) TranslateDistanceExpr(field string, expr string) D
 	if len(match) == 5 && match[3] != "" && match[4] != "" {
 		data.Sql = fmt.Sprintf("%s %s %s", field, match[3], match[4])
 		translator.Translations = append(translator.Translations, data.Sql)
+		translator.Tree = append(translator.Tree, FilterNode{Field: field, Expr: expr, Sql: data.Sql,
+			Parts: []FilterPart{{Operator: match[3], Value: match[4], Sql: data.Sql}}})
 	}
 
 	return data
 }
 
 func TranslateToSql(field string, expr string, exprType ExprType) (string, error) {
-	var sqlCondition string
-	var err error
+	g, translatorFunc := getGrammar(exprType)
+	node, err := translateFilterExpr(field, expr, g, translatorFunc)
 
+	if err != nil {
+		return "", err
+	}
+	return node.Sql, nil
+}
+
+// getGrammar returns the grammar which validates the expressions of a type and the function
+// which translates them to SQL.
+func getGrammar(exprType ExprType) (grammar, func(string, filterExpr) string) {
 	switch exprType {
-	case Str:
-		sqlCondition, err = translateFilterExpr(field, expr, StrRegex, translateStrExpr)
 	case Num:
-		sqlCondition, err = translateFilterExpr(field, expr, NumRegex, translateNumExpr)
+		return numGrammar, translateNumExpr
 	case Lighting:
-		sqlCondition, err = translateFilterExpr(field, expr, LightingRegex, translateStrExpr)
+		return lightingGrammar, translateWordExpr
 	case Amenity:
-		sqlCondition, err = translateFilterExpr(field, expr, AmenityRegex, translateStrExpr)
+		return amenityGrammar, translateAmenityExpr
+	case FullText:
+		return fullTextGrammar, translateTokenizedExpr
 	}
 
-	if err != nil {
-		return "", err
-	}
-	return sqlCondition, nil
+	return strGrammar, translateStrExpr
 }
 
 func translateFilterExpr(
-	field string, filterExpr string, regex string, translatorFunc func(string, filterExpr) string,
-) (string, error) {
-	var translation string
-	expressions, err := splitExpr(filterExpr, regex)
+	field string, filterExpr string, g grammar, translatorFunc func(string, filterExpr) string,
+) (FilterNode, error) {
+	expressions, err := splitExpr(filterExpr, g)
 	if err != nil {
-		return "", err
+		return FilterNode{}, err
 	}
 
-	for _, e := range expressions {
-		translation += fmt.Sprintf("%s and ", translatorFunc(field, e))
+	node := FilterNode{Field: field, Expr: filterExpr}
+	conditions := make([]string, len(expressions))
+	for i, e := range expressions {
+		conditions[i] = translatorFunc(field, e)
+		node.Parts = append(node.Parts, FilterPart{Operator: e.Operator, Value: e.Value, Sql: conditions[i]})
 	}
 
-	translation = strings.TrimSuffix(translation, " and ")
-	return translation, nil
+	node.Sql = strings.Join(conditions, " and ")
+	return node, nil
 }
 
-func splitExpr(expr string, regExpr string) ([]filterExpr, error) {
-	var expressions []filterExpr = make([]filterExpr, 0)
-	parts := strings.Split(expr, Separator)
-	regex := regexp.MustCompile(regExpr)
-
-	for _, p := range parts {
-		match := regex.FindStringSubmatch(p)
-		// Should match 3 parts, the entire string, the operator and the value
-		if match == nil || len(match) != 3 {
-			return []filterExpr{}, fmt.Errorf(`filter expression "%s" in "%s" is not valid`, p, expr)
-		}
+// splitExpr splits an expression in its operators and values, which are normalized. An
+// *ExprError is returned if it is not valid.
+func splitExpr(expr string, g grammar) ([]filterExpr, error) {
+	return exprLexer{expr: expr, grammar: g}.split()
+}
 
-		expressions = append(expressions, filterExpr{Operator: match[1], Value: match[2]})
+func translateNumExpr(field string, e filterExpr) string {
+	switch e.Operator {
+	case "in:":
+		return fmt.Sprintf("%s in (%s)", field, e.Value)
+	case "!in:":
+		return fmt.Sprintf("%s not in (%s)", field, e.Value)
+	case "!=":
+		// Postgres would read "!=-" as a single operator when the value is negative
+		return fmt.Sprintf("%s<>%s", field, e.Value)
 	}
 
-	return expressions, nil
+	return fmt.Sprintf("%s%s%s", field, e.Operator, e.Value)
 }
 
-func translateNumExpr(field string, e filterExpr) string {
-	return fmt.Sprintf("%s%s%s", field, e.Operator, e.Value)
+// translateWordExpr translates expressions over fields with a fixed set of lowercase values,
+// which are compared as they are so the index of the field can be used.
+func translateWordExpr(field string, e filterExpr) string {
+	switch e.Operator {
+	case "!=":
+		return fmt.Sprintf("%s<>'%s'", field, e.Value)
+	case "in:", "!in:":
+		values := strings.Split(e.Value, ",")
+		for i, v := range values {
+			values[i] = fmt.Sprintf("'%s'", strings.TrimSpace(v))
+		}
+		if e.Operator == "!in:" {
+			return fmt.Sprintf("%s not in (%s)", field, strings.Join(values, ", "))
+		}
+		return fmt.Sprintf("%s in (%s)", field, strings.Join(values, ", "))
+	}
+
+	return fmt.Sprintf("%s='%s'", field, e.Value)
 }
 
 func translateStrExpr(field string, e filterExpr) string {
-	if e.Operator == "=" {
+	switch e.Operator {
+	case "=":
 		return fmt.Sprintf("lower(%s)=lower('%s')", field, e.Value)
+	case "!=":
+		// Unlike "<>", fields without a value are also different
+		return fmt.Sprintf("lower(%s) is distinct from lower('%s')", field, e.Value)
+	case "!has:":
+		return fmt.Sprintf("lower(%s) not like lower('%%%s%%')", field, e.Value)
+	case "in:", "!in:":
+		values := strings.Split(e.Value, ",")
+		for i, v := range values {
+			values[i] = fmt.Sprintf("lower('%s')", strings.TrimSpace(v))
+		}
+		if e.Operator == "!in:" {
+			return fmt.Sprintf("lower(%s) not in (%s)", field, strings.Join(values, ", "))
+		}
+		return fmt.Sprintf("lower(%s) in (%s)", field, strings.Join(values, ", "))
+	case "~":
+		// Trigram word similarity, the threshold is set in the database connection
+		return fmt.Sprintf("lower('%s') <%% lower(%s)", e.Value, field)
 	}
 
 	return fmt.Sprintf("lower(%s) like lower('%%%s%%')", field, e.Value)
 }
+
+// translateFullTextExpr translates "match:" expressions to a full text search over the
+// tsvector column of the field, which must be named after it with a "_tsv" suffix.
+func (translator *Translator) translateFullTextExpr(field string, e filterExpr) string {
+	if e.Operator != "match:" {
+		return translateStrExpr(field, e)
+	}
+
+	query := fmt.Sprintf("websearch_to_tsquery('english', '%s')", e.Value)
+	translator.Ranks = append(translator.Ranks, fmt.Sprintf("ts_rank(%s_tsv, %s)", field, query))
+	return fmt.Sprintf("%s_tsv @@ %s", field, query)
+}
+
+// translateTokenizedExpr translates "match:" expressions for backends without full text
+// search: the field must contain every word or quoted phrase, and must not contain the ones
+// preceded by "-".
+func translateTokenizedExpr(field string, e filterExpr) string {
+	if e.Operator != "match:" {
+		return translateStrExpr(field, e)
+	}
+
+	var conditions []string
+	for _, token := range tokenize(e.Value) {
+		if strings.HasPrefix(token, "-") {
+			conditions = append(conditions, fmt.Sprintf("lower(%s) not like lower('%%%s%%')", field, token[1:]))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("lower(%s) like lower('%%%s%%')", field, token))
+		}
+	}
+
+	// Same as a full text search without any words, nothing matches
+	if len(conditions) == 0 {
+		return "false"
+	}
+	return strings.Join(conditions, " and ")
+}
+
+// tokenize splits a full text search value in words and quoted phrases. Exclusions keep
+// their "-" prefix, while separators and the "or" keyword are dropped.
+func tokenize(value string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+
+	flush := func() {
+		token := current.String()
+		current.Reset()
+		if strings.Trim(token, "- ") == "" || strings.EqualFold(token, "or") {
+			return
+		}
+		tokens = append(tokens, token)
+	}
+
+	for _, r := range value {
+		switch {
+		case r == '"':
+			if inQuotes {
+				flush()
+			} else if current.Len() > 0 && current.String() != "-" {
+				flush()
+			}
+			inQuotes = !inQuotes
+		case !inQuotes && (r == ' ' || r == ','):
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// translateAmenityExpr translates the amenity operators to array operators over the field,
+// which is the array of amenities of each property, so they can use its GIN index.
+func translateAmenityExpr(field string, e filterExpr) string {
+	if strings.HasPrefix(e.Operator, "count") {
+		return fmt.Sprintf("cardinality(%s)%s%s", field, strings.TrimPrefix(e.Operator, "count"), e.Value)
+	}
+
+	array := fmt.Sprintf("array['%s']", strings.Join(strings.Split(e.Value, ","), "', '"))
+
+	switch e.Operator {
+	case "=":
+		return fmt.Sprintf("%s = %s", field, array)
+	case "!=":
+		return fmt.Sprintf("%s <> %s", field, array)
+	case "has:", "all:":
+		return fmt.Sprintf("%s @> %s", field, array)
+	case "exactly:":
+		return fmt.Sprintf("%s @> %s and %s <@ %s", field, array, field, array)
+	case "any:", "in:":
+		return fmt.Sprintf("%s && %s", field, array)
+	}
+
+	// "!has:", "!in:" and "none:"
+	return fmt.Sprintf("not %s && %s", field, array)
+}
@@ -23,6 +23,16 @@ func TestTranslateStrExpr(t *testing.T) {
 			expected: "lower(desc) like lower('%alaska%')"},
 		{fieldName: "descriptiON", e: filterExpr{Operator: ":has", Value: "alASka"},
 			expected: "lower(descriptiON) like lower('%alASka%')"},
+		{fieldName: "description", e: filterExpr{Operator: "~", Value: "Pheonix"},
+			expected: "lower('Pheonix') <% lower(description)"},
+		{fieldName: "lighting", e: filterExpr{Operator: "!=", Value: "low"},
+			expected: "lower(lighting) is distinct from lower('low')"},
+		{fieldName: "description", e: filterExpr{Operator: "!has:", Value: "alaska"},
+			expected: "lower(description) not like lower('%alaska%')"},
+		{fieldName: "lighting", e: filterExpr{Operator: "in:", Value: "medium,high"},
+			expected: "lower(lighting) in (lower('medium'), lower('high'))"},
+		{fieldName: "description", e: filterExpr{Operator: "!in:", Value: "house, flat"},
+			expected: "lower(description) not in (lower('house'), lower('flat'))"},
 	}
 
 	for _, test := range testCases {
@@ -32,6 +42,24 @@ func TestTranslateStrExpr(t *testing.T) {
 	}
 }
 
+func TestTranslateWordExpr(t *testing.T) {
+	type testCase struct {
+		e        filterExpr
+		expected string
+	}
+
+	testCases := []testCase{
+		{e: filterExpr{Operator: "=", Value: "low"}, expected: "p.lighting='low'"},
+		{e: filterExpr{Operator: "!=", Value: "low"}, expected: "p.lighting<>'low'"},
+		{e: filterExpr{Operator: "in:", Value: "medium,high"}, expected: "p.lighting in ('medium', 'high')"},
+		{e: filterExpr{Operator: "!in:", Value: "low,medium"}, expected: "p.lighting not in ('low', 'medium')"},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, translateWordExpr("p.lighting", test.e))
+	}
+}
+
 func TestTranslateNumExpr(t *testing.T) {
 	type testCase struct {
 		fieldName string
@@ -48,6 +76,16 @@ func TestTranslateNumExpr(t *testing.T) {
 			expected: "price>=0.158"},
 		{fieldName: "price", e: filterExpr{Operator: "<=", Value: "50000.0"},
 			expected: "price<=50000.0"},
+		{fieldName: "rooms", e: filterExpr{Operator: "!=", Value: "3"},
+			expected: "rooms<>3"},
+		{fieldName: "longitude", e: filterExpr{Operator: "!=", Value: "-74.5"},
+			expected: "longitude<>-74.5"},
+		{fieldName: "latitude", e: filterExpr{Operator: "<=", Value: "-12"},
+			expected: "latitude<=-12"},
+		{fieldName: "rooms", e: filterExpr{Operator: "in:", Value: "2,3,4"},
+			expected: "rooms in (2,3,4)"},
+		{fieldName: "rooms", e: filterExpr{Operator: "!in:", Value: "1,-2.5"},
+			expected: "rooms not in (1,-2.5)"},
 	}
 
 	for _, test := range testCases {
@@ -60,27 +98,42 @@ func TestTranslateNumExpr(t *testing.T) {
 func TestSplitExpr(t *testing.T) {
 	type testCase struct {
 		expr        string
-		regExpr     string
+		g           grammar
 		expected    []filterExpr
 		errExpected bool
 	}
 
 	testCases := []testCase{
-		{expr: "<999", regExpr: NumRegex,
+		{expr: "<999", g: numGrammar,
 			expected: []filterExpr{{Operator: "<", Value: "999"}}, errExpected: false},
-		{expr: ">10000;<=20000", regExpr: NumRegex,
+		{expr: ">10000;<=20000", g: numGrammar,
 			expected: []filterExpr{{Operator: ">", Value: "10000"}, {Operator: "<=", Value: "20000"}}, errExpected: false},
-		{expr: "has:yard;has:pool", regExpr: StrRegex,
+		{expr: "has:yard;has:pool", g: strGrammar,
 			expected: []filterExpr{{Operator: "has:", Value: "yard"}, {Operator: "has:", Value: "pool"}}, errExpected: false},
-		{expr: "has:yard;=test", regExpr: StrRegex,
+		{expr: "has:yard;=test", g: strGrammar,
 			expected: []filterExpr{{Operator: "has:", Value: "yard"}, {Operator: "=", Value: "test"}}, errExpected: false},
-		{expr: "has;yard", regExpr: StrRegex, expected: []filterExpr{}, errExpected: true},
-		{expr: "has::yard;has:pool", regExpr: StrRegex, expected: []filterExpr{}, errExpected: true},
-		{expr: "has:yard;=test;", regExpr: StrRegex, expected: []filterExpr{}, errExpected: true},
+		{expr: "has;yard", g: strGrammar, expected: []filterExpr{}, errExpected: true},
+		{expr: "has::yard;has:pool", g: strGrammar, expected: []filterExpr{}, errExpected: true},
+		{expr: "has:yard;=test;", g: strGrammar, expected: []filterExpr{}, errExpected: true},
+		{expr: "all:pool,garage;count>=3", g: amenityGrammar,
+			expected: []filterExpr{{Operator: "all:", Value: "pool,garage"}, {Operator: "count>=", Value: "3"}}, errExpected: false},
+		{expr: "=yard", g: amenityGrammar, expected: []filterExpr{{Operator: "=", Value: "yard"}}, errExpected: false},
+		{expr: "=yard,pool", g: amenityGrammar, expected: []filterExpr{}, errExpected: true},
+		{expr: "any:pool,sauna", g: amenityGrammar, expected: []filterExpr{}, errExpected: true},
+		{expr: "count>=pool", g: amenityGrammar, expected: []filterExpr{}, errExpected: true},
+		{expr: "!=3;in:2,3,4", g: numGrammar,
+			expected: []filterExpr{{Operator: "!=", Value: "3"}, {Operator: "in:", Value: "2,3,4"}}, errExpected: false},
+		{expr: "in:2,,3", g: numGrammar, expected: []filterExpr{}, errExpected: true},
+		{expr: "!in:low,medium", g: lightingGrammar,
+			expected: []filterExpr{{Operator: "!in:", Value: "low,medium"}}, errExpected: false},
+		{expr: "!=dim", g: lightingGrammar, expected: []filterExpr{}, errExpected: true},
+		{expr: "!has:alaska", g: strGrammar, expected: []filterExpr{{Operator: "!has:", Value: "alaska"}}, errExpected: false},
+		{expr: "!has:pool;!=yard", g: amenityGrammar,
+			expected: []filterExpr{{Operator: "!has:", Value: "pool"}, {Operator: "!=", Value: "yard"}}, errExpected: false},
 	}
 
 	for _, test := range testCases {
-		actual, err := splitExpr(test.expr, test.regExpr)
+		actual, err := splitExpr(test.expr, test.g)
 
 		if test.errExpected {
 			assert.Error(t, err)
@@ -90,3 +143,88 @@ func TestSplitExpr(t *testing.T) {
 		assert.Equal(t, test.expected, actual)
 	}
 }
+
+func TestTranslateFullTextExpr(t *testing.T) {
+	type testCase struct {
+		expr           string
+		fullTextSearch bool
+		expected       []string
+		expectedOrder  string
+	}
+
+	testCases := []testCase{
+		{expr: "match:ocean view", fullTextSearch: true,
+			expected:      []string{"p.description_tsv @@ websearch_to_tsquery('english', 'ocean view')"},
+			expectedOrder: "ts_rank(p.description_tsv, websearch_to_tsquery('english', 'ocean view')) desc"},
+		{expr: "has:ocean", fullTextSearch: true,
+			expected: []string{"lower(p.description) like lower('%ocean%')"}, expectedOrder: ""},
+		{expr: `match:"ocean view" -garage`, fullTextSearch: false,
+			expected: []string{"lower(p.description) like lower('%ocean view%') and " +
+				"lower(p.description) not like lower('%garage%')"}, expectedOrder: ""},
+		{expr: "match:pool or yard", fullTextSearch: false,
+			expected: []string{"lower(p.description) like lower('%pool%') and " +
+				"lower(p.description) like lower('%yard%')"}, expectedOrder: ""},
+		{expr: "match:-", fullTextSearch: false, expected: []string{"false"}, expectedOrder: ""},
+	}
+
+	for _, test := range testCases {
+		translator := Translator{FullTextSearch: test.fullTextSearch}
+		translator.Init()
+		translator.Translate("p.description", test.expr, FullText)
+
+		assert.NoError(t, translator.Err)
+		assert.Equal(t, test.expected, translator.Translations)
+		assert.Equal(t, test.expectedOrder, translator.GetRankOrder())
+	}
+}
+
+func TestGetSimilarityScore(t *testing.T) {
+	type testCase struct {
+		expr     string
+		expected string
+	}
+
+	testCases := []testCase{
+		{expr: "has:ocean", expected: ""},
+		{expr: "~Pheonix", expected: "word_similarity(lower('Pheonix'), lower(p.description))"},
+		{expr: "~Pheonix;~Arizna", expected: "greatest(word_similarity(lower('Pheonix'), lower(p.description)), " +
+			"word_similarity(lower('Arizna'), lower(p.description)))"},
+	}
+
+	for _, test := range testCases {
+		translator := Translator{}
+		translator.Init()
+		translator.Translate("p.description", test.expr, FullText)
+
+		assert.NoError(t, translator.Err)
+		assert.Equal(t, test.expected, translator.GetSimilarityScore())
+	}
+}
+
+func TestTranslateAmenityExpr(t *testing.T) {
+	type testCase struct {
+		e        filterExpr
+		expected string
+	}
+
+	testCases := []testCase{
+		{e: filterExpr{Operator: "has:", Value: "pool"}, expected: "p.amenity_list @> array['pool']"},
+		{e: filterExpr{Operator: "all:", Value: "pool,garage"}, expected: "p.amenity_list @> array['pool', 'garage']"},
+		{e: filterExpr{Operator: "any:", Value: "pool,yard"}, expected: "p.amenity_list && array['pool', 'yard']"},
+		{e: filterExpr{Operator: "none:", Value: "waterfront"}, expected: "not p.amenity_list && array['waterfront']"},
+		{e: filterExpr{Operator: "exactly:", Value: "yard"},
+			expected: "p.amenity_list @> array['yard'] and p.amenity_list <@ array['yard']"},
+		{e: filterExpr{Operator: "!has:", Value: "pool"}, expected: "not p.amenity_list && array['pool']"},
+		{e: filterExpr{Operator: "in:", Value: "pool,yard"}, expected: "p.amenity_list && array['pool', 'yard']"},
+		{e: filterExpr{Operator: "!in:", Value: "pool,yard"}, expected: "not p.amenity_list && array['pool', 'yard']"},
+		{e: filterExpr{Operator: "=", Value: "yard"}, expected: "p.amenity_list = array['yard']"},
+		{e: filterExpr{Operator: "!=", Value: "yard"}, expected: "p.amenity_list <> array['yard']"},
+		{e: filterExpr{Operator: "count>=", Value: "3"}, expected: "cardinality(p.amenity_list)>=3"},
+	}
+
+	for _, test := range testCases {
+		actual := translateAmenityExpr("p.amenity_list", test.e)
+
+		assert.Equal(t, test.expected, actual)
+	}
+}
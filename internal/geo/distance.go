@@ -0,0 +1,23 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package geo
+
+import "math"
+
+const earthRadiusMiles = 3958.939
+
+// SphericDistance approximates the distance in miles between two coordinates using the
+// Haversine formula. It matches the fn_spheric_distance function created in the database.
+func SphericDistance(x1 float64, y1 float64, x2 float64, y2 float64) float64 {
+	x1Radians := x1 * math.Pi / 180
+	y1Radians := y1 * math.Pi / 180
+	x2Radians := x2 * math.Pi / 180
+	y2Radians := y2 * math.Pi / 180
+
+	havTheta := (1 - math.Cos(x1Radians-x2Radians)) / 2
+	havPhi := (1 - math.Cos(y1Radians-y2Radians)) / 2
+	havAlpha := havTheta + math.Cos(x1Radians)*math.Cos(x2Radians)*havPhi
+
+	return 2 * earthRadiusMiles * math.Asin(math.Sqrt(havAlpha))
+}
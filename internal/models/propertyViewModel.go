@@ -4,6 +4,7 @@ Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
 package models
 
 type PropertyViewModel struct {
+	ID             uint
 	Description    string
 	Price          float32
 	Square_footage float32
@@ -14,4 +15,5 @@ type PropertyViewModel struct {
 	Lighting       string
 	Amenities      string
 	Dist           float32
+	Similarity     float32
 }
@@ -0,0 +1,177 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package querylog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ta-ma/prop-filter-app/internal/filter"
+)
+
+// Entry is a query which was run, recording the fields it filtered by but not their values.
+type Entry struct {
+	Time    time.Time
+	Filters []FieldUse
+	Order   string
+}
+
+// FieldUse is a field which was filtered by, along with the operators used.
+type FieldUse struct {
+	Field     string
+	Operators []string
+}
+
+// Suggestion is a composite index which would help the queries filtering by all its columns.
+type Suggestion struct {
+	Columns []string
+	Queries int
+}
+
+// Operators which compare for equality, so their columns go first in composite indexes
+var equalityOperators = []string{"=", "in:"}
+
+// NewEntry returns the entry of a query filtered by the parsed expressions.
+func NewEntry(tree []filter.FilterNode, order string) Entry {
+	entry := Entry{Time: time.Now(), Order: order}
+	for _, node := range tree {
+		use := FieldUse{Field: node.Field}
+		for _, part := range node.Parts {
+			if !slices.Contains(use.Operators, part.Operator) {
+				use.Operators = append(use.Operators, part.Operator)
+			}
+		}
+		entry.Filters = append(entry.Filters, use)
+	}
+
+	return entry
+}
+
+// Append writes the entry at the end of the log, creating it if it doesn't exist.
+func Append(path string, entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// Read returns the entries of the log. A log which doesn't exist has no entries.
+func Read(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// Suggest returns the composite indexes for the combinations of columns filtered together in
+// at least minQueries entries, most frequent first. Fields are mapped to the column indexed
+// for them, and fields without a column are ignored. In each index, columns only compared for
+// equality go before the rest, since only the first range condition can use the index.
+func Suggest(entries []Entry, columns map[string]string, minQueries int) []Suggestion {
+	counts := make(map[string]int)
+	// Whether each column of a combination has only been compared for equality
+	equality := make(map[string]map[string]bool)
+
+	for _, entry := range entries {
+		used := make(map[string]bool)
+		for _, use := range entry.Filters {
+			column, ok := columns[use.Field]
+			if !ok {
+				continue
+			}
+
+			onlyEquality := true
+			for _, o := range use.Operators {
+				onlyEquality = onlyEquality && slices.Contains(equalityOperators, o)
+			}
+			// A column can be filtered twice, such as by its flag and by --where
+			previous, seen := used[column]
+			used[column] = onlyEquality && (!seen || previous)
+		}
+
+		if len(used) < 2 {
+			continue
+		}
+
+		key := combinationKey(used)
+		counts[key]++
+		if equality[key] == nil {
+			equality[key] = make(map[string]bool)
+			for column, eq := range used {
+				equality[key][column] = eq
+			}
+			continue
+		}
+		for column, eq := range used {
+			equality[key][column] = equality[key][column] && eq
+		}
+	}
+
+	var suggestions []Suggestion
+	for key, count := range counts {
+		if count < minQueries {
+			continue
+		}
+
+		cols := strings.Split(key, ",")
+		sort.SliceStable(cols, func(i, j int) bool {
+			return equality[key][cols[i]] && !equality[key][cols[j]]
+		})
+		suggestions = append(suggestions, Suggestion{Columns: cols, Queries: count})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Queries != suggestions[j].Queries {
+			return suggestions[i].Queries > suggestions[j].Queries
+		}
+		return strings.Join(suggestions[i].Columns, ",") < strings.Join(suggestions[j].Columns, ",")
+	})
+
+	return suggestions
+}
+
+func combinationKey(used map[string]bool) string {
+	columns := make([]string, 0, len(used))
+	for column := range used {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	return strings.Join(columns, ",")
+}
@@ -0,0 +1,70 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package querylog
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testColumns = map[string]string{
+	"p.price":     "price",
+	"p.rooms":     "rooms",
+	"p.bathrooms": "bathrooms",
+	"p.lighting":  "lighting",
+}
+
+func entry(filters ...FieldUse) Entry {
+	return Entry{Filters: filters}
+}
+
+func TestSuggest(t *testing.T) {
+	type testCase struct {
+		entries    []Entry
+		minQueries int
+		expected   []Suggestion
+	}
+
+	price := FieldUse{Field: "p.price", Operators: []string{">=", "<="}}
+	rooms := FieldUse{Field: "p.rooms", Operators: []string{"="}}
+	roomsRange := FieldUse{Field: "p.rooms", Operators: []string{">"}}
+	lighting := FieldUse{Field: "p.lighting", Operators: []string{"in:"}}
+	description := FieldUse{Field: "p.description", Operators: []string{"has:"}}
+
+	testCases := []testCase{
+		{entries: nil, minQueries: 1, expected: nil},
+		{entries: []Entry{entry(price), entry(price)}, minQueries: 1, expected: nil},
+		{entries: []Entry{entry(price, description)}, minQueries: 1, expected: nil},
+		{entries: []Entry{entry(price, rooms), entry(rooms, price)}, minQueries: 2,
+			expected: []Suggestion{{Columns: []string{"rooms", "price"}, Queries: 2}}},
+		{entries: []Entry{entry(price, rooms), entry(price, roomsRange)}, minQueries: 2,
+			expected: []Suggestion{{Columns: []string{"price", "rooms"}, Queries: 2}}},
+		{entries: []Entry{entry(price, rooms), entry(price, rooms, lighting), entry(price, rooms, lighting)}, minQueries: 2,
+			expected: []Suggestion{{Columns: []string{"lighting", "rooms", "price"}, Queries: 2}}},
+		{entries: []Entry{entry(price, rooms)}, minQueries: 2, expected: nil},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, Suggest(test.entries, testColumns, test.minQueries))
+	}
+}
+
+func TestAppendAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queries.log")
+
+	entries, err := Read(path)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	first := entry(FieldUse{Field: "p.price", Operators: []string{"<"}})
+	second := Entry{Filters: []FieldUse{{Field: "p.rooms", Operators: []string{"="}}}, Order: "p.price desc"}
+	assert.NoError(t, Append(path, first))
+	assert.NoError(t, Append(path, second))
+
+	entries, err = Read(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []Entry{first, second}, entries)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ta-ma/prop-filter-app/internal/columns"
+)
+
+type pickerItem struct {
+	column  columns.Column
+	enabled bool
+}
+
+// columnPicker lets the user choose which columns are displayed and in which order.
+type columnPicker struct {
+	items  []pickerItem
+	cursor int
+	err    string
+}
+
+// newColumnPicker lists the displayed columns first, in their current order, followed by
+// the rest of the available ones.
+func newColumnPicker(displayed []columns.Column, available []columns.Column) columnPicker {
+	var picker columnPicker
+	shown := make(map[string]bool)
+	for _, c := range displayed {
+		picker.items = append(picker.items, pickerItem{column: c, enabled: true})
+		shown[c.Key] = true
+	}
+	for _, c := range available {
+		if !shown[c.Key] {
+			picker.items = append(picker.items, pickerItem{column: c})
+		}
+	}
+
+	return picker
+}
+
+// update handles a key press. Returns whether the picker has been closed and the
+// selected columns, which are nil if the selection has been cancelled.
+func (p *columnPicker) update(msg tea.KeyMsg, keys keyMap) (bool, []columns.Column) {
+	p.err = ""
+	switch {
+	case key.Matches(msg, keys.MoveUp):
+		if p.cursor > 0 {
+			p.items[p.cursor], p.items[p.cursor-1] = p.items[p.cursor-1], p.items[p.cursor]
+			p.cursor--
+		}
+	case key.Matches(msg, keys.MoveDown):
+		if p.cursor < len(p.items)-1 {
+			p.items[p.cursor], p.items[p.cursor+1] = p.items[p.cursor+1], p.items[p.cursor]
+			p.cursor++
+		}
+	case key.Matches(msg, keys.Up):
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case key.Matches(msg, keys.Down):
+		if p.cursor < len(p.items)-1 {
+			p.cursor++
+		}
+	case key.Matches(msg, keys.Mark):
+		p.items[p.cursor].enabled = !p.items[p.cursor].enabled
+	case key.Matches(msg, keys.Confirm):
+		var selected []columns.Column
+		for _, item := range p.items {
+			if item.enabled {
+				selected = append(selected, item.column)
+			}
+		}
+		if len(selected) == 0 {
+			p.err = "At least one column must be selected"
+			return false, nil
+		}
+		return true, selected
+	case key.Matches(msg, keys.Cancel, keys.Columns):
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// view renders the picker followed by the help of its keys.
+func (p columnPicker) view(keysHelp string) string {
+	var lines []string
+	lines = append(lines, "Select the columns to display:", "")
+	for i, item := range p.items {
+		cursor := "  "
+		if i == p.cursor {
+			cursor = "> "
+		}
+		check := "[ ]"
+		if item.enabled {
+			check = "[x]"
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %s", cursor, check, item.column.Title))
+	}
+
+	if p.err != "" {
+		lines = append(lines, "", p.err)
+	}
+
+	lines = append(lines, "", keysHelp)
+
+	return baseStyle.Padding(0, 1).Render(strings.Join(lines, "\n")) + "\n"
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package render
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/charmbracelet/lipgloss"
+	ltable "github.com/charmbracelet/lipgloss/table"
+	"github.com/ta-ma/prop-filter-app/internal/columns"
+	"github.com/ta-ma/prop-filter-app/internal/models"
+)
+
+const (
+	compareLabelWidth = 14
+	compareMinWidth   = 12
+)
+
+// compareView shows the marked properties side by side, one property per column and one
+// field per row, highlighting the best value of each field.
+func compareView(props []models.PropertyViewModel, cols []columns.Column, width int) string {
+	// Each property column takes its content, 2 characters of padding and a border
+	cellWidth := compareMinWidth
+	if width > 0 {
+		cellWidth = max((width-compareLabelWidth-4)/len(props)-3, compareMinWidth)
+	}
+
+	headers := []string{""}
+	for _, p := range props {
+		headers = append(headers, fmt.Sprintf("#%d", p.ID))
+	}
+
+	var rows [][]string
+	best := make(map[[2]int]bool)
+	for _, c := range cols {
+		row := []string{truncate(c.Title, compareLabelWidth)}
+		for _, p := range props {
+			row = append(row, truncate(c.Format(p), cellWidth))
+		}
+		for _, i := range bestValues(props, c) {
+			best[[2]int{len(rows), i + 1}] = true
+		}
+		rows = append(rows, row)
+	}
+
+	t := ltable.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(baseStyle.GetBorderTopForeground())).
+		Headers(headers...).
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			style := lipgloss.NewStyle().Padding(0, 1)
+			if row != ltable.HeaderRow && best[[2]int{row, col}] {
+				style = style.Inherit(bestValueStyle)
+			}
+			return style
+		})
+
+	return lipgloss.NewStyle().Padding(1, 0).Render(t.Render()) + "\n"
+}
+
+// bestValues returns the indexes of the properties with the best value of the column. There is
+// no best value if the column has no preference or all the properties share the same value.
+// Undefined values are never the best.
+func bestValues(props []models.PropertyViewModel, c columns.Column) []int {
+	if c.Value == nil || c.Best == columns.NoPreference || len(props) < 2 {
+		return nil
+	}
+
+	first := c.Value(props[0])
+	bestValue := math.NaN()
+	allEqual := true
+	for _, p := range props {
+		v := c.Value(p)
+		allEqual = allEqual && (v == first || (math.IsNaN(v) && math.IsNaN(first)))
+		if math.IsNaN(v) {
+			continue
+		}
+		if math.IsNaN(bestValue) || (c.Best == columns.Lowest && v < bestValue) || (c.Best == columns.Highest && v > bestValue) {
+			bestValue = v
+		}
+	}
+
+	if allEqual || math.IsNaN(bestValue) {
+		return nil
+	}
+
+	var result []int
+	for i, p := range props {
+		if c.Value(p) == bestValue {
+			result = append(result, i)
+		}
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package render
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ta-ma/prop-filter-app/internal/config"
+	"github.com/ta-ma/prop-filter-app/internal/db"
+	"github.com/ta-ma/prop-filter-app/internal/geo"
+	"github.com/ta-ma/prop-filter-app/internal/models"
+)
+
+const similarPropertiesLimit = 5
+
+// detailView shows every field of a single property along with related data.
+type detailView struct {
+	property models.PropertyViewModel
+	similar  []models.PropertyViewModel
+	loading  bool
+	err      error
+	// Cancels the query of similar properties when the view is closed
+	cancel context.CancelFunc
+}
+
+// similarLoadedMsg is sent when the properties similar to the displayed one have been queried.
+type similarLoadedMsg struct {
+	id    uint
+	props []models.PropertyViewModel
+	err   error
+}
+
+func newDetailView(ctx context.Context, repo *db.Repository, property models.PropertyViewModel) (*detailView, tea.Cmd) {
+	ctx, cancel := context.WithCancel(ctx)
+	view := &detailView{property: property, loading: true, cancel: cancel}
+	cmd := func() tea.Msg {
+		props, err := repo.QuerySimilarProperties(ctx, property, similarPropertiesLimit)
+		return similarLoadedMsg{id: property.ID, props: props, err: err}
+	}
+
+	return view, cmd
+}
+
+func (v *detailView) setSimilar(msg similarLoadedMsg) {
+	if msg.id != v.property.ID {
+		return
+	}
+
+	v.loading = false
+	v.similar = msg.props
+	v.err = msg.err
+}
+
+func (v detailView) view(width int, calcDistance bool, points []config.Point) string {
+	p := v.property
+	var lines []string
+
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Property #%d", p.ID)))
+	lines = append(lines, sectionStyle.Render("Description"))
+	lines = append(lines, lipgloss.NewStyle().Width(max(width-2, 0)).Render(p.Description))
+
+	lines = append(lines, sectionStyle.Render("Details"))
+	lines = append(lines, fmt.Sprintf("Price: $%.2f", p.Price))
+	lines = append(lines, fmt.Sprintf("Square ft: %.2f", p.Square_footage))
+	lines = append(lines, fmt.Sprintf("Price per square ft: $%.2f", p.Price/p.Square_footage))
+	lines = append(lines, fmt.Sprintf("Rooms: %d", p.Rooms))
+	lines = append(lines, fmt.Sprintf("Bathrooms: %d", p.Bathrooms))
+	lines = append(lines, fmt.Sprintf("Lighting: %s", p.Lighting))
+	lines = append(lines, fmt.Sprintf("Coordinates: %.6f, %.6f", p.Latitude, p.Longitude))
+	if calcDistance {
+		lines = append(lines, fmt.Sprintf("Distance: %.2f miles", p.Dist))
+	}
+
+	lines = append(lines, sectionStyle.Render("Amenities"))
+	if p.Amenities == "" {
+		lines = append(lines, "None")
+	}
+	for _, a := range strings.Split(p.Amenities, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			lines = append(lines, "• "+a)
+		}
+	}
+
+	if len(points) > 0 {
+		lines = append(lines, sectionStyle.Render("Distance to points"))
+		for _, point := range points {
+			dist := geo.SphericDistance(p.Latitude, p.Longitude, point.Latitude, point.Longitude)
+			lines = append(lines, fmt.Sprintf("%s: %.2f miles", point.Name, dist))
+		}
+	}
+
+	lines = append(lines, sectionStyle.Render("Similar properties nearby"))
+	switch {
+	case v.loading:
+		lines = append(lines, "Loading...")
+	case v.err != nil:
+		lines = append(lines, fmt.Sprintf("Similar properties could not be queried: %s", v.err))
+	case len(v.similar) == 0:
+		lines = append(lines, "None found")
+	}
+	for _, s := range v.similar {
+		line := fmt.Sprintf("#%d  $%.2f  %d rooms  %d bathrooms  %.2f miles away  %s",
+			s.ID, s.Price, s.Rooms, s.Bathrooms, s.Dist, s.Description)
+		lines = append(lines, truncate(line, width-2))
+	}
+
+	return lipgloss.NewStyle().
+		Padding(1).
+		Render(strings.Join(lines, "\n")) + "\n"
+}
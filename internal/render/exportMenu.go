@@ -0,0 +1,171 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package render
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ta-ma/prop-filter-app/internal/columns"
+	"github.com/ta-ma/prop-filter-app/internal/export"
+	"github.com/ta-ma/prop-filter-app/internal/models"
+)
+
+type exportScope int
+
+const (
+	currentPageScope exportScope = iota
+	allResultsScope
+	markedScope
+)
+
+type exportOption struct {
+	key    string
+	label  string
+	scope  exportScope
+	format export.Format
+}
+
+var exportOptions = []exportOption{
+	{key: "1", label: "Current page as CSV", scope: currentPageScope, format: export.CSV},
+	{key: "2", label: "Current page as JSON", scope: currentPageScope, format: export.JSON},
+	{key: "3", label: "All results as CSV", scope: allResultsScope, format: export.CSV},
+	{key: "4", label: "All results as JSON", scope: allResultsScope, format: export.JSON},
+	{key: "5", label: "Marked properties as CSV", scope: markedScope, format: export.CSV},
+	{key: "6", label: "Marked properties as JSON", scope: markedScope, format: export.JSON},
+}
+
+// exportDoneMsg is sent when the properties have been written to a file.
+type exportDoneMsg struct {
+	path  string
+	count int
+	err   error
+}
+
+func (m model) updateExportMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Cancel, m.keys.Export):
+		m.exportMenu = false
+		return m, nil
+	}
+
+	for _, option := range exportOptions {
+		if msg.String() != option.key {
+			continue
+		}
+
+		m.exportMenu = false
+		if option.scope == markedScope && len(m.marked) == 0 {
+			m.status = "There are no marked properties to export"
+			return m, nil
+		}
+		m.status = "Exporting..."
+		return m, m.exportProperties(option)
+	}
+
+	return m, nil
+}
+
+// exportProperties writes the properties in the background to a file in the working directory.
+func (m model) exportProperties(option exportOption) tea.Cmd {
+	scopeNames := map[exportScope]string{currentPageScope: "page", allResultsScope: "all", markedScope: "marked"}
+	path := fmt.Sprintf("properties-%s-%s.%s", scopeNames[option.scope], time.Now().Format("20060102-150405"),
+		option.format.Extension())
+
+	props := m.props
+	if option.scope == markedScope {
+		props = m.marked
+	}
+	ctx, repo, query, propsCount, cols := m.ctx, m.repo, m.query, m.propsCount, m.columns
+
+	return func() tea.Msg {
+		if option.scope == allResultsScope {
+			var err error
+			props, err = repo.QueryProperties(ctx, query.filter, query.order, propsCount, 0, query.calcDistance, query.distX, query.distY, query.similarity)
+			if err != nil {
+				return exportDoneMsg{err: err}
+			}
+		}
+
+		err := export.WriteFile(path, option.format, props, cols)
+		return exportDoneMsg{path: path, count: len(props), err: err}
+	}
+}
+
+// exportMenuView renders the export options followed by the help of the keys.
+func exportMenuView(keysHelp string) string {
+	lines := []string{"Export properties to a file:", ""}
+	for _, option := range exportOptions {
+		lines = append(lines, fmt.Sprintf("%s: %s", option.key, option.label))
+	}
+	lines = append(lines, "", keysHelp)
+
+	return baseStyle.Padding(0, 1).Render(strings.Join(lines, "\n")) + "\n"
+}
+
+// copyToClipboard sets the terminal clipboard using an OSC52 escape sequence. It is written
+// to stderr so it doesn't interfere with the table rendering.
+func copyToClipboard(text string) error {
+	_, err := osc52.New(text).WriteTo(os.Stderr)
+	return err
+}
+
+// rowText returns the ID and displayed values of a property separated by tabs.
+func rowText(property models.PropertyViewModel, cols []columns.Column) string {
+	values := append([]string{fmt.Sprintf("%d", property.ID)}, columns.Row(property, cols)...)
+	return strings.Join(values, "\t")
+}
+
+// commandLine rebuilds the query command which lists the properties as currently displayed.
+func (m model) commandLine() string {
+	args := []string{"prop-filter-app", "query"}
+	for i := 0; i+1 < len(m.filterArgs); i += 2 {
+		args = append(args, m.filterArgs[i], shellQuote(m.filterArgs[i+1]))
+	}
+
+	if !m.autoHeight {
+		args = append(args, "--page-size", fmt.Sprintf("%d", m.pageHeight))
+	}
+
+	keys := make([]string, len(m.columns))
+	for i, c := range m.columns {
+		keys[i] = c.Key
+	}
+	defaultKeys := make([]string, 0)
+	for _, c := range columns.Default(m.query.calcDistance, m.query.similarity != "") {
+		defaultKeys = append(defaultKeys, c.Key)
+	}
+	if strings.Join(keys, ",") != strings.Join(defaultKeys, ",") {
+		args = append(args, "--columns", strings.Join(keys, ","))
+	}
+
+	if m.sortKey != "" {
+		sort := m.sortKey
+		if m.sortDesc {
+			sort += ":desc"
+		}
+		args = append(args, "--sort", sort)
+	}
+
+	return strings.Join(args, " ")
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func exportStatus(msg exportDoneMsg) string {
+	if msg.err != nil {
+		return fmt.Sprintf("Export failed: %s", msg.err)
+	}
+
+	return fmt.Sprintf("Exported %d properties to %s", msg.count, msg.path)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type keyMap struct {
+	Up          key.Binding
+	Down        key.Binding
+	PrevPage    key.Binding
+	NextPage    key.Binding
+	SkipBack    key.Binding
+	SkipForward key.Binding
+	FirstPage   key.Binding
+	LastPage    key.Binding
+	GoToPage    key.Binding
+	GoToID      key.Binding
+	Details     key.Binding
+	Back        key.Binding
+	Mark        key.Binding
+	UnmarkAll   key.Binding
+	Compare     key.Binding
+	Columns     key.Binding
+	Sort        key.Binding
+	ReverseSort key.Binding
+	Export      key.Binding
+	CopyRow     key.Binding
+	CopyCommand key.Binding
+	Help        key.Binding
+	Quit        key.Binding
+	// Used by the column picker, the export menu and the prompts
+	Confirm  key.Binding
+	Cancel   key.Binding
+	MoveUp   key.Binding
+	MoveDown key.Binding
+}
+
+// keyScopes are the actions available at the same time, in the table or in one of the views
+// opened from it, which can't share keys.
+var keyScopes = map[string][]string{
+	"table": {"up", "down", "prevpage", "nextpage", "skipback", "skipforward", "firstpage", "lastpage", "gotopage",
+		"gotoid", "details", "back", "mark", "unmarkall", "compare", "columns", "sort", "reversesort", "export",
+		"copyrow", "copycommand", "help", "quit"},
+	"column picker": {"up", "down", "moveup", "movedown", "mark", "confirm", "cancel", "columns", "quit"},
+	"export menu":   {"cancel", "export", "quit"},
+	"prompt":        {"confirm", "cancel", "quit"},
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Up:          key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "move up")),
+		Down:        key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "move down")),
+		PrevPage:    key.NewBinding(key.WithKeys("left"), key.WithHelp("←", "previous page")),
+		NextPage:    key.NewBinding(key.WithKeys("right"), key.WithHelp("→", "next page")),
+		SkipBack:    key.NewBinding(key.WithKeys("pgup"), key.WithHelp("pgup", "10 pages back")),
+		SkipForward: key.NewBinding(key.WithKeys("pgdown"), key.WithHelp("pgdn", "10 pages forward")),
+		FirstPage:   key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "first page")),
+		LastPage:    key.NewBinding(key.WithKeys("G"), key.WithHelp("G", "last page")),
+		GoToPage:    key.NewBinding(key.WithKeys(":"), key.WithHelp(":", "go to page")),
+		GoToID:      key.NewBinding(key.WithKeys("#"), key.WithHelp("#", "go to property ID")),
+		Details:     key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "details")),
+		Back:        key.NewBinding(key.WithKeys("esc", "backspace"), key.WithHelp("esc", "back")),
+		Mark:        key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "mark")),
+		UnmarkAll:   key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "unmark all")),
+		Compare:     key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "compare marked")),
+		Columns:     key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "choose columns")),
+		Sort:        key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sort by next column")),
+		ReverseSort: key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "reverse sort")),
+		Export:      key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "export")),
+		CopyRow:     key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy property")),
+		CopyCommand: key.NewBinding(key.WithKeys("Y"), key.WithHelp("Y", "copy query command")),
+		Help:        key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		Quit:        key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+		Confirm:     key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "confirm")),
+		Cancel:      key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+		MoveUp:      key.NewBinding(key.WithKeys("shift+up", "K"), key.WithHelp("shift+↑/K", "move column up")),
+		MoveDown:    key.NewBinding(key.WithKeys("shift+down", "J"), key.WithHelp("shift+↓/J", "move column down")),
+	}
+}
+
+// newKeyMap returns the default key map with the bindings replaced by the keys configured
+// for each action.
+func newKeyMap(config map[string][]string) (keyMap, error) {
+	keys := defaultKeyMap()
+	bindings := keys.byName()
+
+	for name, configKeys := range config {
+		binding, ok := bindings[strings.ToLower(name)]
+		if !ok {
+			names := make([]string, 0, len(bindings))
+			for n := range bindings {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return keyMap{}, fmt.Errorf(`unknown key binding "%s", available bindings are: %s`, name, strings.Join(names, ", "))
+		}
+		if len(configKeys) == 0 {
+			return keyMap{}, fmt.Errorf(`key binding "%s" must have at least one key`, name)
+		}
+
+		binding.SetKeys(configKeys...)
+		binding.SetHelp(strings.Join(configKeys, "/"), binding.Help().Desc)
+	}
+
+	if err := checkDuplicateKeys(bindings); err != nil {
+		return keyMap{}, err
+	}
+	return keys, nil
+}
+
+// checkDuplicateKeys returns an error if a key is bound to more than one of the actions
+// available at the same time.
+func checkDuplicateKeys(bindings map[string]*key.Binding) error {
+	scopes := make([]string, 0, len(keyScopes))
+	for scope := range keyScopes {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+
+	for _, scope := range scopes {
+		actions := make(map[string]string)
+		for _, name := range keyScopes[scope] {
+			for _, k := range bindings[name].Keys() {
+				if other, ok := actions[k]; ok {
+					return fmt.Errorf(`key "%s" is bound to both "%s" and "%s" in the %s`, k, other, name, scope)
+				}
+				actions[k] = name
+			}
+		}
+	}
+
+	return nil
+}
+
+func (k *keyMap) byName() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up": &k.Up, "down": &k.Down, "prevpage": &k.PrevPage, "nextpage": &k.NextPage,
+		"skipback": &k.SkipBack, "skipforward": &k.SkipForward, "firstpage": &k.FirstPage,
+		"lastpage": &k.LastPage, "gotopage": &k.GoToPage, "gotoid": &k.GoToID, "details": &k.Details,
+		"back": &k.Back, "mark": &k.Mark, "unmarkall": &k.UnmarkAll, "compare": &k.Compare,
+		"columns": &k.Columns, "sort": &k.Sort, "reversesort": &k.ReverseSort, "export": &k.Export, "copyrow": &k.CopyRow,
+		"copycommand": &k.CopyCommand, "help": &k.Help, "quit": &k.Quit, "confirm": &k.Confirm, "cancel": &k.Cancel,
+		"moveup": &k.MoveUp, "movedown": &k.MoveDown,
+	}
+}
+
+// withHelp returns a copy of the binding described as desc, for the views where its action is
+// different.
+func withHelp(binding key.Binding, desc string) key.Binding {
+	binding.SetHelp(binding.Help().Key, desc)
+	return binding
+}
+
+// ShortHelp implements the help.KeyMap interface.
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.PrevPage, k.NextPage, k.Details, k.Mark, k.Help, k.Quit}
+}
+
+// FullHelp implements the help.KeyMap interface.
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.PrevPage, k.NextPage, k.SkipBack, k.SkipForward, k.FirstPage, k.LastPage},
+		{k.GoToPage, k.GoToID, k.Details, k.Back, k.Columns, k.Sort, k.ReverseSort},
+		{k.Mark, k.UnmarkAll, k.Compare},
+		{k.Export, k.CopyRow, k.CopyCommand, k.Help, k.Quit},
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewKeyMap(t *testing.T) {
+	type testCase struct {
+		config      map[string][]string
+		errExpected bool
+	}
+
+	testCases := []testCase{
+		{config: nil, errExpected: false},
+		{config: map[string][]string{"NextPage": {"right", "l"}, "PrevPage": {"left", "h"}}, errExpected: false},
+		{config: map[string][]string{"NextPage": {"right", "k"}}, errExpected: true},
+		{config: map[string][]string{"Confirm": {"enter", " "}}, errExpected: true},
+		// Enter opens the details in the table and confirms in the prompts
+		{config: map[string][]string{"Confirm": {"enter", "tab"}}, errExpected: false},
+		{config: map[string][]string{"Cancel": {"q"}}, errExpected: true},
+		{config: map[string][]string{"NextStep": {"n"}}, errExpected: true},
+		{config: map[string][]string{"Quit": {}}, errExpected: true},
+	}
+
+	for _, test := range testCases {
+		_, err := newKeyMap(test.config)
+
+		if test.errExpected {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+	}
+}
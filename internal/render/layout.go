@@ -0,0 +1,83 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package render
+
+import (
+	"sort"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/mattn/go-runewidth"
+	"github.com/ta-ma/prop-filter-app/internal/columns"
+)
+
+// Each table cell is rendered with 1 character of padding on both sides
+const cellPadding = 2
+
+// layoutColumns fits the columns in the given width. Columns that don't fit even with their
+// minimum width are hidden (width 0) starting from the lowest priority ones, the remaining
+// space is shared among the visible columns proportionally to their preferred width.
+// If width is not positive the preferred widths are used.
+func layoutColumns(cols []columns.Column, width int) []table.Column {
+	result := make([]table.Column, len(cols))
+	for i, c := range cols {
+		result[i] = table.Column{Title: c.Title, Width: c.Width}
+	}
+
+	if width <= 0 {
+		return result
+	}
+
+	order := make([]int, len(cols))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return cols[order[a]].Priority < cols[order[b]].Priority
+	})
+
+	used := 0
+	preferred := 0
+	visible := make([]bool, len(cols))
+	for _, i := range order {
+		if used+cols[i].MinWidth+cellPadding > width {
+			continue
+		}
+		visible[i] = true
+		used += cols[i].MinWidth + cellPadding
+		preferred += cols[i].Width
+	}
+
+	extra := width - used
+	remaining := extra
+	firstVisible := -1
+	for i, c := range cols {
+		if !visible[i] {
+			result[i].Width = 0
+			continue
+		}
+		if firstVisible == -1 {
+			firstVisible = i
+		}
+
+		grow := extra * c.Width / preferred
+		result[i].Width = c.MinWidth + grow
+		remaining -= grow
+	}
+
+	// Leftovers from rounding go to the first visible column
+	if firstVisible != -1 {
+		result[firstVisible].Width += remaining
+	}
+
+	return result
+}
+
+// truncate shortens str to the given terminal cell width, adding an ellipsis if it was cut.
+func truncate(str string, width int) string {
+	if width <= 0 {
+		return str
+	}
+
+	return runewidth.Truncate(str, width, "…")
+}
@@ -0,0 +1,70 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package render
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Lines between the top of the table and its first row: the border, the header and its border
+const tableHeaderLines = 3
+
+func (m model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	// The table is displayed right below the details area
+	tableTop := strings.Count(m.getDetails(), "\n")
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		if m.table.Cursor() == 0 && m.currentPage > 1 {
+			return m, m.goToPage(m.currentPage-1, m.pageHeight-1)
+		}
+		m.table.MoveUp(1)
+	case tea.MouseButtonWheelDown:
+		if m.table.Cursor() == len(m.table.Rows())-1 && m.currentPage < m.maxPage {
+			return m, m.goToPage(m.currentPage+1, 0)
+		}
+		m.table.MoveDown(1)
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			break
+		}
+
+		if msg.Y == tableTop+1 {
+			// The first column only shows if the property is marked
+			if i := columnAt(m.table.Columns(), msg.X); i > 0 {
+				return m, m.sortBy(m.columns[i-1])
+			}
+		}
+
+		row := msg.Y - tableTop - tableHeaderLines
+		if row >= 0 && row < len(m.table.Rows()) {
+			m.table.SetCursor(row)
+		}
+	}
+
+	return m, nil
+}
+
+// columnAt returns the index of the column displayed at the given horizontal position,
+// or -1 if there is none.
+func columnAt(cols []table.Column, x int) int {
+	// The table border takes the first character
+	start := 1
+	for i, c := range cols {
+		if c.Width <= 0 {
+			continue
+		}
+
+		end := start + c.Width + cellPadding
+		if x >= start && x < end {
+			return i
+		}
+		start = end
+	}
+
+	return -1
+}
@@ -0,0 +1,215 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package render
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/ta-ma/prop-filter-app/internal/db"
+	"github.com/ta-ma/prop-filter-app/internal/models"
+)
+
+const defaultPageCacheSize = 32
+
+// The displayed page and the two pages prefetched next to it
+const minPageCacheSize = 3
+
+// pageQuery holds every parameter that changes which properties are listed.
+type pageQuery struct {
+	filter       string
+	order        string
+	calcDistance bool
+	distX        string
+	distY        string
+	similarity   string
+}
+
+type pageKey struct {
+	query      pageQuery
+	pageHeight int
+	page       int
+}
+
+type pageEntry struct {
+	key   pageKey
+	props []models.PropertyViewModel
+	err   error
+	ready chan struct{}
+	// Cancels the query of the page if it is still loading
+	cancel context.CancelFunc
+}
+
+// pageCache is a LRU cache of already queried pages. Loads of the same page are
+// deduplicated, so a page being prefetched in the background is not queried twice.
+type pageCache struct {
+	// Context of every query, loads are cancelled when it is done
+	ctx context.Context
+	// Queries the properties of a page
+	loader   func(context.Context, pageKey) ([]models.PropertyViewModel, error)
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[pageKey]*list.Element
+}
+
+func newPageCache(ctx context.Context, repo *db.Repository, capacity int) *pageCache {
+	if capacity < 1 {
+		capacity = defaultPageCacheSize
+	}
+
+	return &pageCache{
+		ctx: ctx,
+		loader: func(ctx context.Context, key pageKey) ([]models.PropertyViewModel, error) {
+			q := key.query
+			return repo.QueryProperties(ctx, q.filter, q.order, key.pageHeight, (key.page-1)*key.pageHeight, q.calcDistance, q.distX, q.distY, q.similarity)
+		},
+		capacity: max(capacity, minPageCacheSize),
+		order:    list.New(),
+		entries:  make(map[pageKey]*list.Element),
+	}
+}
+
+// get returns the properties of a page, loading it in the background unless it is cached or
+// already loading. It stops waiting when ctx is done, but the load goes on until the cache
+// cancels it, so the page can still be used when it is prefetched.
+func (c *pageCache) get(ctx context.Context, key pageKey) ([]models.PropertyViewModel, error) {
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	if ok {
+		c.order.MoveToFront(elem)
+	} else {
+		elem = c.start(key)
+	}
+	entry := elem.Value.(*pageEntry)
+	c.mu.Unlock()
+
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	// The load was cancelled because the page was not needed anymore, but now it is
+	if errors.Is(entry.err, context.Canceled) && ctx.Err() == nil && c.ctx.Err() == nil {
+		return c.get(ctx, key)
+	}
+	return entry.props, entry.err
+}
+
+// prefetch starts loading a page in the background unless it is cached or already loading.
+func (c *pageCache) prefetch(key pageKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok {
+		c.start(key)
+	}
+}
+
+// cached returns the properties of a page if it has already been loaded.
+func (c *pageCache) cached(key pageKey) ([]models.PropertyViewModel, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*pageEntry)
+	select {
+	case <-entry.ready:
+		c.order.MoveToFront(elem)
+		return entry.props, entry.err == nil
+	default:
+		return nil, false
+	}
+}
+
+// start adds a page to the cache and loads it in the background. c.mu must be held.
+func (c *pageCache) start(key pageKey) *list.Element {
+	ctx, cancel := context.WithCancel(c.ctx)
+	entry := &pageEntry{key: key, ready: make(chan struct{}), cancel: cancel}
+	elem := c.order.PushFront(entry)
+	c.entries[key] = elem
+	c.evict()
+
+	go func() {
+		entry.props, entry.err = c.loader(ctx, key)
+		cancel()
+		close(entry.ready)
+
+		// Failed loads are not cached so they can be retried
+		if entry.err != nil {
+			c.remove(entry)
+		}
+	}()
+	return elem
+}
+
+// purge removes every cached page which does not belong to the given query.
+func (c *pageCache) purge(keep pageQuery) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.entries {
+		if key.query != keep {
+			c.drop(elem)
+		}
+	}
+}
+
+// cancelExcept cancels the pages which are still loading, other than the given ones.
+func (c *pageCache) cancelExcept(keep []pageKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.entries {
+		select {
+		case <-elem.Value.(*pageEntry).ready:
+			continue
+		default:
+		}
+
+		if !slices.Contains(keep, key) {
+			c.drop(elem)
+		}
+	}
+}
+
+func (c *pageCache) remove(entry *pageEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[entry.key]; ok && elem.Value == entry {
+		c.order.Remove(elem)
+		delete(c.entries, entry.key)
+	}
+}
+
+// evict drops the least recently used pages until the cache fits its capacity. Pages which
+// are still loading are kept, since they are about to be displayed or prefetched.
+func (c *pageCache) evict() {
+	for elem := c.order.Back(); elem != nil && c.order.Len() > c.capacity; {
+		prev := elem.Prev()
+		select {
+		case <-elem.Value.(*pageEntry).ready:
+			c.drop(elem)
+		default:
+		}
+		elem = prev
+	}
+}
+
+// drop removes a page from the cache, cancelling its load if it is in progress.
+func (c *pageCache) drop(elem *list.Element) {
+	entry := elem.Value.(*pageEntry)
+	entry.cancel()
+	c.order.Remove(elem)
+	delete(c.entries, entry.key)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package render
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ta-ma/prop-filter-app/internal/models"
+)
+
+// fakeLoader returns a property with the page number as its ID and counts the loads of each page.
+type fakeLoader struct {
+	mu      sync.Mutex
+	calls   map[int]int
+	started chan int
+	// Loads of these pages wait until their channel is closed or they are cancelled
+	blocked map[int]chan struct{}
+}
+
+func newFakeCache(capacity int, blockedPages ...int) (*pageCache, *fakeLoader) {
+	loader := &fakeLoader{calls: make(map[int]int), started: make(chan int, 16), blocked: make(map[int]chan struct{})}
+	for _, page := range blockedPages {
+		loader.blocked[page] = make(chan struct{})
+	}
+
+	cache := newPageCache(context.Background(), nil, capacity)
+	cache.loader = loader.load
+	return cache, loader
+}
+
+func (f *fakeLoader) load(ctx context.Context, key pageKey) ([]models.PropertyViewModel, error) {
+	f.mu.Lock()
+	f.calls[key.page]++
+	release := f.blocked[key.page]
+	f.mu.Unlock()
+
+	if release != nil {
+		f.started <- key.page
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return []models.PropertyViewModel{{ID: uint(key.page)}}, nil
+}
+
+func (f *fakeLoader) loads(page int) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[page]
+}
+
+func pageOf(page int) pageKey {
+	return pageKey{query: pageQuery{order: "p.price asc"}, pageHeight: 10, page: page}
+}
+
+type pageResult struct {
+	props []models.PropertyViewModel
+	err   error
+}
+
+// getAsync loads a page in the background, waiting until its query has started if it is blocked.
+func getAsync(ctx context.Context, cache *pageCache, loader *fakeLoader, page int) chan pageResult {
+	result := make(chan pageResult, 1)
+	go func() {
+		props, err := cache.get(ctx, pageOf(page))
+		result <- pageResult{props, err}
+	}()
+	if _, ok := loader.blocked[page]; ok && loader.loads(page) == 0 {
+		<-loader.started
+	}
+	return result
+}
+
+func TestPageCacheDeduplicatesLoads(t *testing.T) {
+	cache, loader := newFakeCache(3, 1)
+
+	first := getAsync(context.Background(), cache, loader, 1)
+	second := getAsync(context.Background(), cache, loader, 1)
+	close(loader.blocked[1])
+
+	for _, result := range []pageResult{<-first, <-second} {
+		assert.NoError(t, result.err)
+		assert.Equal(t, []models.PropertyViewModel{{ID: 1}}, result.props)
+	}
+	assert.Equal(t, 1, loader.loads(1))
+}
+
+func TestPageCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache, loader := newFakeCache(3)
+
+	for _, page := range []int{1, 2, 3, 1, 4} {
+		_, err := cache.get(context.Background(), pageOf(page))
+		assert.NoError(t, err)
+	}
+
+	// Page 2 was the least recently used when page 4 was added
+	for _, page := range []int{1, 3, 4, 2} {
+		cache.get(context.Background(), pageOf(page))
+	}
+	assert.Equal(t, map[int]int{1: 1, 2: 2, 3: 1, 4: 1}, loader.calls)
+}
+
+func TestPageCacheKeepsLoadingPages(t *testing.T) {
+	// Capacities below the displayed page and its neighbours are raised
+	cache, loader := newFakeCache(1, 1)
+
+	loading := getAsync(context.Background(), cache, loader, 1)
+	for _, page := range []int{2, 3, 4, 5} {
+		cache.get(context.Background(), pageOf(page))
+	}
+	close(loader.blocked[1])
+
+	result := <-loading
+	assert.NoError(t, result.err)
+	assert.Equal(t, []models.PropertyViewModel{{ID: 1}}, result.props)
+}
+
+func TestPageCacheCancelExcept(t *testing.T) {
+	cache, loader := newFakeCache(3, 1, 2)
+
+	// Pages which are no longer needed stop being waited for and are cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	kept := getAsync(context.Background(), cache, loader, 1)
+	cancelled := getAsync(ctx, cache, loader, 2)
+	cancel()
+	cache.cancelExcept([]pageKey{pageOf(1)})
+
+	assert.ErrorIs(t, (<-cancelled).err, context.Canceled)
+	close(loader.blocked[1])
+	assert.NoError(t, (<-kept).err)
+
+	// Cancelled pages are queried again when they are needed
+	close(loader.blocked[2])
+	_, err := cache.get(context.Background(), pageOf(2))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, loader.loads(2))
+}
+
+func TestPageCachePurge(t *testing.T) {
+	cache, loader := newFakeCache(3)
+	cache.get(context.Background(), pageOf(1))
+
+	sorted := pageOf(1)
+	sorted.query.order = "p.rooms desc"
+	cache.get(context.Background(), sorted)
+	cache.purge(sorted.query)
+
+	cache.get(context.Background(), sorted)
+	cache.get(context.Background(), pageOf(1))
+	assert.Equal(t, 3, loader.loads(1))
+}
+
+func TestPageCacheStopsWaiting(t *testing.T) {
+	cache, loader := newFakeCache(3, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	waiting := getAsync(ctx, cache, loader, 1)
+	cancel()
+	assert.ErrorIs(t, (<-waiting).err, context.Canceled)
+
+	// The load goes on, so the page is ready once it is needed again
+	close(loader.blocked[1])
+	props, err := cache.get(context.Background(), pageOf(1))
+	assert.NoError(t, err)
+	assert.Equal(t, []models.PropertyViewModel{{ID: 1}}, props)
+	assert.Equal(t, 1, loader.loads(1))
+
+	cached, ok := cache.cached(pageOf(1))
+	assert.True(t, ok)
+	assert.Equal(t, props, cached)
+	_, ok = cache.cached(pageOf(2))
+	assert.False(t, ok)
+}
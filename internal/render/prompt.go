@@ -0,0 +1,147 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package render
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Amount of pages skipped with PgUp/PgDn
+const pageJump = 10
+
+type promptKind int
+
+const (
+	pagePrompt promptKind = iota
+	idPrompt
+)
+
+// jumpPrompt reads a page number or property ID to navigate to.
+type jumpPrompt struct {
+	kind  promptKind
+	input textinput.Model
+	err   string
+	// Cancels the search of the property position, nil if it isn't being searched
+	cancel context.CancelFunc
+}
+
+// positionFoundMsg is sent when the position of the property entered in a prompt is found.
+type positionFoundMsg struct {
+	prompt   *jumpPrompt
+	position int
+	err      error
+}
+
+func newJumpPrompt(kind promptKind) *jumpPrompt {
+	input := textinput.New()
+	input.CharLimit = 10
+	input.Validate = func(s string) error {
+		if strings.Trim(s, "0123456789") != "" {
+			return fmt.Errorf("only digits are allowed")
+		}
+		return nil
+	}
+
+	if kind == pagePrompt {
+		input.Prompt = "Go to page: "
+	} else {
+		input.Prompt = "Go to property ID: "
+	}
+	input.Focus()
+
+	return &jumpPrompt{kind: kind, input: input}
+}
+
+func (p jumpPrompt) view() string {
+	view := p.input.View()
+	if p.cancel != nil {
+		view += "  Searching..."
+	}
+	if p.err != "" {
+		view += "  " + errorStyle.Render(p.err)
+	}
+
+	return lipgloss.NewStyle().Padding(0, 1).Render(view)
+}
+
+func (m model) updateJumpPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	// Keys typed into the input don't quit
+	case msg.Type != tea.KeyRunes && key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Cancel):
+		if m.prompt.cancel != nil {
+			m.prompt.cancel()
+		}
+		m.prompt = nil
+		return m, nil
+	case key.Matches(msg, m.keys.Confirm):
+		if m.prompt.cancel != nil {
+			return m, nil
+		}
+
+		value, err := strconv.Atoi(m.prompt.input.Value())
+		if err != nil {
+			m.prompt.err = "A number is required"
+			return m, nil
+		}
+
+		if m.prompt.kind == idPrompt {
+			return m, m.findPosition(uint(value))
+		}
+		return m.jumpTo(value, -1)
+	}
+
+	var cmd tea.Cmd
+	m.prompt.err = ""
+	m.prompt.input, cmd = m.prompt.input.Update(msg)
+	return m, cmd
+}
+
+// findPosition searches the position of a property in the background, so the table can still
+// be used, or the search cancelled, while the query runs.
+func (m model) findPosition(id uint) tea.Cmd {
+	ctx, cancel := context.WithCancel(m.ctx)
+	prompt, repo, q := m.prompt, m.repo, m.query
+	prompt.err, prompt.cancel = "", cancel
+
+	return func() tea.Msg {
+		position, err := repo.GetPropertyPosition(ctx, id, q.filter, q.order, q.calcDistance, q.distX, q.distY, q.similarity)
+		return positionFoundMsg{prompt: prompt, position: position, err: err}
+	}
+}
+
+func (m model) positionFound(msg positionFoundMsg) (tea.Model, tea.Cmd) {
+	// The prompt was closed while searching
+	if msg.prompt != m.prompt {
+		return m, nil
+	}
+	m.prompt.cancel()
+	m.prompt.cancel = nil
+
+	if msg.err != nil {
+		m.prompt.err = loadError(msg.err)
+		return m, nil
+	}
+	return m.jumpTo((msg.position-1)/m.pageHeight+1, (msg.position-1)%m.pageHeight)
+}
+
+// jumpTo closes the prompt and displays the given page, if it exists.
+func (m model) jumpTo(page int, cursor int) (tea.Model, tea.Cmd) {
+	if page < 1 || page > m.maxPage {
+		m.prompt.err = fmt.Sprintf("Page must be between 1 and %d", m.maxPage)
+		return m, nil
+	}
+
+	m.prompt = nil
+	return m, m.goToPage(page, cursor)
+}
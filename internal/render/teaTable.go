@@ -4,86 +4,572 @@ Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
 package render
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ta-ma/prop-filter-app/internal/columns"
+	"github.com/ta-ma/prop-filter-app/internal/config"
 	"github.com/ta-ma/prop-filter-app/internal/db"
 	"github.com/ta-ma/prop-filter-app/internal/models"
 )
 
-var baseStyle = lipgloss.NewStyle().
-	BorderStyle(lipgloss.NormalBorder()).
-	BorderForeground(lipgloss.Color("240"))
+// The first column of the table shows whether the property has been marked
+const markColumnWidth = 1
 
 type model struct {
-	table        table.Model
+	table      table.Model
+	keys       keyMap
+	help       help.Model
+	showHelp   bool
+	columns    []columns.Column
+	props      []models.PropertyViewModel
+	marked     []models.PropertyViewModel
+	comparing  bool
+	exportMenu bool
+	status     string
+	filterArgs []string
+	picker     *columnPicker
+	detail     *detailView
+	prompt     *jumpPrompt
+	sortKey    string
+	sortDesc   bool
+	// Order used when the properties aren't sorted by a column
+	defaultOrder string
+	points       []config.Point
 	currentPage  int
 	maxPage      int
 	pageHeight   int
-	queryFilter  string
-	calcDistance bool
-	distX        string
-	distY        string
+	propsCount   int
+	autoHeight   bool
+	width        int
+	height       int
+	query        pageQuery
+	cache        *pageCache
+	// Page being loaded to be displayed, nil if the displayed one is up to date
+	loading *pageLoad
+	// Cancelled when the table is closed, stopping the queries in progress
+	ctx  context.Context
+	repo *db.Repository
 }
 
-func (m model) Init() tea.Cmd { return nil }
+// pageLoad is a page being loaded in the background to be displayed.
+type pageLoad struct {
+	cursor int
+	// State displayed before the load, restored if it fails
+	previous listState
+	// Stops waiting for the page, such as when another one is displayed instead
+	cancel context.CancelFunc
+}
+
+// pageLoadedMsg is sent when the page to be displayed has been loaded.
+type pageLoadedMsg struct {
+	load  *pageLoad
+	props []models.PropertyViewModel
+	err   error
+}
+
+func (m model) Init() tea.Cmd {
+	m.prefetchNeighbours()
+	return nil
+}
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var tableChanged bool
+	cursor := -1
+	previous := m.saveState()
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width - 2
+		m.table.SetColumns(m.tableColumns())
+		if m.autoHeight {
+			tableChanged, cursor = m.resizePage(msg.Height)
+		}
+	case tea.MouseMsg:
+		if m.picker == nil && m.detail == nil && m.prompt == nil && !m.comparing && !m.exportMenu && !m.showHelp {
+			return m.updateMouse(msg)
+		}
+		return m, nil
+	case pageLoadedMsg:
+		m.pageLoaded(msg)
+		return m, nil
+	case positionFoundMsg:
+		return m.positionFound(msg)
+	case exportDoneMsg:
+		m.status = exportStatus(msg)
+		return m, nil
+	case similarLoadedMsg:
+		if m.detail != nil {
+			m.detail.setSimilar(msg)
+		}
+		return m, nil
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "left":
-			if m.currentPage > 1 {
-				m.currentPage--
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		if m.picker != nil {
+			return m.updateColumnPicker(msg)
+		}
+		if m.detail != nil {
+			return m.updateDetailView(msg)
+		}
+		if m.prompt != nil {
+			return m.updateJumpPrompt(msg)
+		}
+		if m.comparing {
+			return m.updateCompareView(msg)
+		}
+		if m.exportMenu {
+			return m.updateExportMenu(msg)
+		}
+
+		m.status = ""
+		switch {
+		case key.Matches(msg, m.keys.PrevPage):
+			return m, m.goToPage(m.currentPage-1, -1)
+		case key.Matches(msg, m.keys.NextPage):
+			return m, m.goToPage(m.currentPage+1, -1)
+		case key.Matches(msg, m.keys.SkipBack):
+			return m, m.goToPage(m.currentPage-pageJump, -1)
+		case key.Matches(msg, m.keys.SkipForward):
+			return m, m.goToPage(m.currentPage+pageJump, -1)
+		case key.Matches(msg, m.keys.FirstPage):
+			return m, m.goToPage(1, 0)
+		case key.Matches(msg, m.keys.LastPage):
+			return m, m.goToPage(m.maxPage, 0)
+		case key.Matches(msg, m.keys.GoToPage):
+			m.prompt = newJumpPrompt(pagePrompt)
+			return m, textinput.Blink
+		case key.Matches(msg, m.keys.GoToID):
+			m.prompt = newJumpPrompt(idPrompt)
+			return m, textinput.Blink
+		case key.Matches(msg, m.keys.Sort):
+			return m, m.sortByNext()
+		case key.Matches(msg, m.keys.ReverseSort):
+			return m, m.reverseSort()
+		case key.Matches(msg, m.keys.Columns):
+			picker := newColumnPicker(m.columns, columns.Available(m.query.calcDistance, m.query.similarity != ""))
+			m.picker = &picker
+			return m, nil
+		case key.Matches(msg, m.keys.Details):
+			cursor := m.table.Cursor()
+			if cursor < 0 || cursor >= len(m.props) {
+				return m, nil
+			}
+			m.detail, cmd = newDetailView(m.ctx, m.repo, m.props[cursor])
+			return m, cmd
+		case key.Matches(msg, m.keys.Mark):
+			cursor := m.table.Cursor()
+			if cursor < 0 || cursor >= len(m.props) {
+				return m, nil
 			}
-			tableChanged = true
-		case "right":
-			if m.currentPage < m.maxPage {
-				m.currentPage++
+			m.toggleMark(m.props[cursor])
+			return m, nil
+		case key.Matches(msg, m.keys.UnmarkAll):
+			m.marked = nil
+			m.table.SetRows(m.mapPropertiesToRows(m.props))
+			return m, nil
+		case key.Matches(msg, m.keys.Compare):
+			m.comparing = len(m.marked) > 0
+			return m, nil
+		case key.Matches(msg, m.keys.Export):
+			m.exportMenu = true
+			return m, nil
+		case key.Matches(msg, m.keys.CopyRow):
+			cursor := m.table.Cursor()
+			if cursor < 0 || cursor >= len(m.props) {
+				return m, nil
 			}
-			tableChanged = true
-		case "q", "ctrl+c":
+			m.status = "Selected property copied to the clipboard"
+			if err := copyToClipboard(rowText(m.props[cursor], m.columns)); err != nil {
+				m.status = fmt.Sprintf("Could not copy to the clipboard: %s", err)
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.CopyCommand):
+			m.status = "Query command copied to the clipboard"
+			if err := copyToClipboard(m.commandLine()); err != nil {
+				m.status = fmt.Sprintf("Could not copy to the clipboard: %s", err)
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Help):
+			m.showHelp = true
+			return m, nil
+		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
 		}
 	}
 
+	var loadCmd tea.Cmd
 	if tableChanged {
-		rows, err := getTableRows(m.queryFilter, m.pageHeight, m.currentPage, m.calcDistance, m.distX, m.distY)
-		if err != nil {
-			panic("Error while rebuilding the table! Exiting...")
-		}
-		m.table.SetRows(rows)
+		loadCmd = m.reloadPage(cursor, previous)
 	}
 	m.table, cmd = m.table.Update(msg)
-	return m, cmd
+
+	return m, tea.Batch(cmd, loadCmd)
+}
+
+func (m model) updateColumnPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, m.keys.Quit) {
+		return m, tea.Quit
+	}
+
+	closed, cols := m.picker.update(msg, m.keys)
+	if !closed {
+		return m, nil
+	}
+
+	m.picker = nil
+	if cols == nil {
+		return m, nil
+	}
+
+	// Rows can't have more values than the table has columns, so they are cleared first
+	previous := m.saveState()
+	cursor := m.table.Cursor()
+	m.columns = cols
+	m.table.SetRows(nil)
+	m.table.SetColumns(m.tableColumns())
+	m.table.SetRows(m.mapPropertiesToRows(m.props))
+
+	// The details area height depends on the amount of columns
+	if m.autoHeight && m.height > 0 {
+		if changed, c := m.resizePage(m.height); changed {
+			cursor = c
+		}
+	}
+	return m, m.reloadPage(cursor, previous)
+}
+
+func (m model) updateDetailView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.detail.cancel()
+		m.detail = nil
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+// sortBy sorts the properties by the given column, cycling between ascending, descending and
+// the default order when it is called repeatedly for the same column.
+func (m *model) sortBy(col columns.Column) tea.Cmd {
+	switch {
+	case m.sortKey != col.Key:
+		return m.setSort(col, false)
+	case !m.sortDesc:
+		return m.setSort(col, true)
+	}
+	return m.setSort(columns.Column{}, false)
+}
+
+// sortByNext sorts the properties by the displayed column after the one they are sorted by,
+// going back to the default order after the last one.
+func (m *model) sortByNext() tea.Cmd {
+	next := 0
+	for i, c := range m.columns {
+		if c.Key == m.sortKey {
+			next = i + 1
+		}
+	}
+
+	if next == len(m.columns) {
+		return m.setSort(columns.Column{}, false)
+	}
+	return m.setSort(m.columns[next], false)
+}
+
+// reverseSort reverses the order of the column the properties are sorted by, if any.
+func (m *model) reverseSort() tea.Cmd {
+	for _, c := range m.columns {
+		if c.Key == m.sortKey {
+			return m.setSort(c, !m.sortDesc)
+		}
+	}
+	return nil
+}
+
+// setSort sorts the properties by the given column, or by the default order if it has no key,
+// and displays the first page.
+func (m *model) setSort(col columns.Column, desc bool) tea.Cmd {
+	previous := m.saveState()
+	m.sortKey, m.sortDesc = col.Key, desc
+
+	query := m.query
+	query.order = m.defaultOrder
+	if m.sortKey != "" {
+		query.order = col.OrderBy(m.sortDesc)
+	}
+	m.setQuery(query)
+	m.table.SetColumns(m.tableColumns())
+
+	m.currentPage = 1
+	return m.reloadPage(0, previous)
+}
+
+// tableColumns fits the columns to the terminal width and marks the one used to sort.
+func (m model) tableColumns() []table.Column {
+	// The table border takes 1 character on each side
+	cols := layoutColumns(m.columns, m.width-2-markColumnWidth-cellPadding)
+	for i, c := range m.columns {
+		if c.Key != m.sortKey {
+			continue
+		}
+
+		if m.sortDesc {
+			cols[i].Title += " ▼"
+		} else {
+			cols[i].Title += " ▲"
+		}
+	}
+
+	return append([]table.Column{{Title: "", Width: markColumnWidth}}, cols...)
+}
+
+// goToPage displays the given page, which is clamped to the available ones, and moves the
+// selection to the given row, or keeps it where it was if cursor is -1.
+func (m *model) goToPage(page int, cursor int) tea.Cmd {
+	page = max(min(page, m.maxPage), 1)
+	if page == m.currentPage && cursor == -1 {
+		return nil
+	}
+
+	previous := m.saveState()
+	m.currentPage = page
+	return m.reloadPage(cursor, previous)
+}
+
+func (m model) updateCompareView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back, m.keys.Compare):
+		m.comparing = false
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+// toggleMark marks the property to be compared, or unmarks it if it already was.
+func (m *model) toggleMark(property models.PropertyViewModel) {
+	if i := m.markedIndex(property.ID); i != -1 {
+		m.marked = append(m.marked[:i:i], m.marked[i+1:]...)
+	} else {
+		m.marked = append(m.marked, property)
+	}
+
+	m.table.SetRows(m.mapPropertiesToRows(m.props))
+}
+
+func (m model) markedIndex(id uint) int {
+	for i, p := range m.marked {
+		if p.ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// listState holds what determines the displayed page, so it can be restored if another page
+// can't be loaded.
+type listState struct {
+	query       pageQuery
+	sortKey     string
+	sortDesc    bool
+	columns     []columns.Column
+	currentPage int
+	maxPage     int
+	pageHeight  int
+}
+
+func (m model) saveState() listState {
+	return listState{query: m.query, sortKey: m.sortKey, sortDesc: m.sortDesc, columns: m.columns,
+		currentPage: m.currentPage, maxPage: m.maxPage, pageHeight: m.pageHeight}
+}
+
+// reloadPage displays the rows of the current page and moves the selection to the given
+// row, or keeps it where it was if cursor is -1. Pages which aren't cached are loaded in the
+// background while the previous one stays displayed, and the load of any other page to be
+// displayed is cancelled.
+func (m *model) reloadPage(cursor int, previous listState) tea.Cmd {
+	if m.loading != nil {
+		m.loading.cancel()
+		previous = m.loading.previous
+		m.loading = nil
+	}
+	m.prefetchNeighbours()
+
+	key := pageKey{query: m.query, pageHeight: m.pageHeight, page: m.currentPage}
+	if props, ok := m.cache.cached(key); ok {
+		m.showPage(props, cursor)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	load := &pageLoad{cursor: cursor, previous: previous, cancel: cancel}
+	m.loading = load
+	cache := m.cache
+	return func() tea.Msg {
+		props, err := cache.get(ctx, key)
+		return pageLoadedMsg{load: load, props: props, err: err}
+	}
+}
+
+// pageLoaded displays a page loaded in the background. If it can't be loaded, the error is
+// shown in the status line and the previous state is displayed again.
+func (m *model) pageLoaded(msg pageLoadedMsg) {
+	// Another page was requested while this one was loading
+	if msg.load != m.loading {
+		return
+	}
+	m.loading.cancel()
+	m.loading = nil
+
+	if msg.err != nil {
+		m.status = loadErrorStatus(m.currentPage, msg.err)
+		m.restoreState(msg.load.previous)
+		return
+	}
+	m.showPage(msg.props, msg.load.cursor)
+}
+
+func (m *model) showPage(props []models.PropertyViewModel, cursor int) {
+	m.props = props
+	m.table.SetRows(m.mapPropertiesToRows(props))
+	if cursor != -1 {
+		m.table.SetCursor(cursor)
+	}
+}
+
+// restoreState goes back to a previous state, displaying the properties loaded for it.
+func (m *model) restoreState(state listState) {
+	headerHeight := lipgloss.Height(m.table.View()) - m.table.Height()
+	m.query, m.sortKey, m.sortDesc, m.columns = state.query, state.sortKey, state.sortDesc, state.columns
+	m.currentPage, m.maxPage, m.pageHeight = state.currentPage, state.maxPage, state.pageHeight
+
+	m.table.SetRows(nil)
+	m.table.SetColumns(m.tableColumns())
+	m.table.SetHeight(m.pageHeight + headerHeight)
+	m.table.SetRows(m.mapPropertiesToRows(m.props))
+}
+
+func loadErrorStatus(page int, err error) string {
+	return fmt.Sprintf("Could not load page %d: %s", page, loadError(err))
+}
+
+// loadError describes why properties could not be loaded.
+func loadError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "the query timed out"
+	}
+	return err.Error()
+}
+
+// resizePage fits the page size to the terminal height, keeping the selected property
+// visible. Returns whether the page changed and the new cursor position.
+func (m *model) resizePage(termHeight int) (bool, int) {
+	chromeHeight := lipgloss.Height(m.listView()) - m.table.Height()
+	headerHeight := lipgloss.Height(m.table.View()) - m.table.Height()
+	pageHeight := max(termHeight-chromeHeight, 1)
+	if pageHeight == m.pageHeight {
+		return false, -1
+	}
+
+	selected := (m.currentPage-1)*m.pageHeight + m.table.Cursor()
+	m.pageHeight = pageHeight
+	m.maxPage = getMaxPage(m.propsCount, pageHeight)
+	m.currentPage = selected/pageHeight + 1
+	m.table.SetHeight(pageHeight + headerHeight)
+
+	return true, selected % pageHeight
+}
+
+// setQuery changes the listed properties, dropping cached pages of the previous query.
+func (m *model) setQuery(query pageQuery) {
+	if query != m.query {
+		m.cache.purge(query)
+	}
+	m.query = query
+}
+
+// prefetchNeighbours loads the previous and next pages into the cache in the background,
+// cancelling the loads of pages which are no longer next to the current one.
+func (m model) prefetchNeighbours() {
+	keys := []pageKey{{query: m.query, pageHeight: m.pageHeight, page: m.currentPage}}
+	for _, page := range []int{m.currentPage + 1, m.currentPage - 1} {
+		if page >= 1 && page <= m.maxPage {
+			keys = append(keys, pageKey{query: m.query, pageHeight: m.pageHeight, page: page})
+		}
+	}
+
+	m.cache.cancelExcept(keys)
+	for _, key := range keys[1:] {
+		m.cache.prefetch(key)
+	}
 }
 
 func (m model) View() string {
+	backKeysInfo := lipgloss.NewStyle().
+		Padding(0, 1).
+		Render(m.help.ShortHelpView([]key.Binding{m.keys.Back, m.keys.Quit})) + "\n"
+
+	switch {
+	case m.showHelp:
+		return lipgloss.NewStyle().Padding(1, 0).Render(
+			baseStyle.Padding(0, 1).Render(m.help.FullHelpView(m.keys.FullHelp())+"\n\nPress any key to close")) + "\n"
+	case m.picker != nil:
+		pickerKeysInfo := m.help.ShortHelpView([]key.Binding{m.keys.Up, m.keys.Down, m.keys.MoveUp, m.keys.MoveDown,
+			withHelp(m.keys.Mark, "show/hide"), withHelp(m.keys.Confirm, "apply"), m.keys.Cancel})
+		return lipgloss.NewStyle().Padding(1, 0).Render(m.picker.view(pickerKeysInfo)) + "\n" +
+			m.getPageInfo() + "\n"
+	case m.detail != nil:
+		return m.detail.view(m.width, m.query.calcDistance, m.points) + backKeysInfo
+	case m.comparing:
+		return compareView(m.marked, columns.Available(m.query.calcDistance, m.query.similarity != ""), m.width) + backKeysInfo
+	}
+
+	return m.listView()
+}
+
+func (m model) listView() string {
+	footer := m.getKeysInfo()
+	if m.prompt != nil {
+		footer = m.prompt.view()
+	}
+	if m.exportMenu {
+		footer = exportMenuView(m.help.ShortHelpView([]key.Binding{m.keys.Cancel}))
+	}
+
 	return m.getDetails() +
 		baseStyle.Render(m.table.View()) + "\n" +
 		m.getPageInfo() + "\n" +
-		m.getKeysInfo() + "\n"
+		footer + "\n"
 }
 
 func (m model) getDetails() string {
 	var lines []string
 	row := m.table.SelectedRow()
-	columns := []string{
-		"Description", "Price", "Square ft", "Rooms", "Bathrooms", "Lighting", "Location",
-	}
-
-	if m.calcDistance {
-		columns = append(columns, "Distance")
-	}
-	columns = append(columns, "Amenities")
 
-	for i, c := range columns {
-		lines = append(lines, fmt.Sprintf("%s: %s", c, row[i]))
+	for i, c := range m.columns {
+		var value string
+		if i+1 < len(row) {
+			value = row[i+1]
+		}
+		// Lines must not wrap, the details area height has to stay the same between rows
+		lines = append(lines, truncate(fmt.Sprintf("%s: %s", c.Title, value), m.width-2))
 	}
 
 	return lipgloss.NewStyle().
@@ -94,94 +580,122 @@ func (m model) getDetails() string {
 func (m model) getKeysInfo() string {
 	return lipgloss.NewStyle().
 		Padding(0, 1).
-		Render("Up/Down: Move selection   Left/Right: Change page   Q: Exit")
+		Render(m.help.View(m.keys))
 }
 
 func (m model) getPageInfo() string {
+	info := fmt.Sprintf("Page %d / %d   Marked: %d", m.currentPage, m.maxPage, len(m.marked))
+	if m.loading != nil {
+		info += "   Loading..."
+	}
+	if m.status != "" {
+		info += "   " + m.status
+	}
+
 	return lipgloss.NewStyle().
 		Padding(0, 1).
-		Render(fmt.Sprintf("Page %d / %d\n", m.currentPage, m.maxPage))
+		Render(truncate(info, m.width-2) + "\n")
+}
+
+// ValidateConfig checks the settings of the table in the configuration.
+func ValidateConfig(cliConfig *config.Cli) error {
+	if _, err := getTheme(cliConfig.Theme); err != nil {
+		return fmt.Errorf("invalid theme configuration: %w", err)
+	}
+	if _, err := newKeyMap(cliConfig.KeyMap); err != nil {
+		return fmt.Errorf("invalid key map configuration: %w", err)
+	}
+
+	return nil
 }
 
-func ShowTeaTable(startPageNumber int, pageHeight int, maxPage int, queryFilter string, calcDistance bool, distX string, distY string) {
-	rows, err := getTableRows(queryFilter, pageHeight, startPageNumber, calcDistance, distX, distY)
+// ShowTeaTable displays the properties in an interactive table. If autoHeight is true, the
+// page size is adjusted to the terminal height and pageHeight is only used until it is known.
+// The filterArgs are the flags and values used to filter the properties, in pairs, and
+// queryOrder is the order used when the properties aren't sorted by a column. If sort is not
+// nil, the properties are sorted by its column at first.
+func ShowTeaTable(ctx context.Context, repo *db.Repository, startPageNumber int, pageHeight int, autoHeight bool, propsCount int, cols []columns.Column, filterArgs []string, queryFilter string, queryOrder string, sort *columns.Sort, calcDistance bool, distX string, distY string, similarity string, cliConfig *config.Cli) {
+	selectedTheme, err := getTheme(cliConfig.Theme)
 	if err != nil {
+		fmt.Println("Invalid theme configuration:", err)
 		return
 	}
+	applyTheme(selectedTheme)
 
-	columns := []table.Column{
-		{Title: "Description", Width: 30},
-		{Title: "Price", Width: 10},
-		{Title: "Square ft", Width: 10},
-		{Title: "Rooms", Width: 6},
-		{Title: "Bathrooms", Width: 10},
-		{Title: "Lighting", Width: 10},
-		{Title: "Location", Width: 16},
+	keys, err := newKeyMap(cliConfig.KeyMap)
+	if err != nil {
+		fmt.Println("Invalid key map configuration:", err)
+		return
 	}
 
-	if calcDistance {
-		columns = append(columns, table.Column{Title: "Distance", Width: 10})
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	helpModel := help.New()
+	helpModel.Styles = helpStyles
+
+	m := model{keys: keys, help: helpModel, columns: cols, filterArgs: filterArgs, points: cliConfig.Points,
+		currentPage: startPageNumber, maxPage: getMaxPage(propsCount, pageHeight), pageHeight: pageHeight,
+		propsCount: propsCount, autoHeight: autoHeight, defaultOrder: queryOrder,
+		query: pageQuery{filter: queryFilter, order: queryOrder, calcDistance: calcDistance, distX: distX, distY: distY,
+			similarity: similarity},
+		cache: newPageCache(ctx, repo, cliConfig.PageCacheSize), ctx: ctx, repo: repo}
+	if sort != nil {
+		m.sortKey, m.sortDesc = sort.Column.Key, sort.Desc
+		m.query.order = sort.Column.OrderBy(sort.Desc)
+	}
+
+	props, err := m.cache.get(ctx, pageKey{query: m.query, pageHeight: pageHeight, page: startPageNumber})
+	if err != nil {
+		fmt.Println("Failed to load properties:", loadError(err))
+		return
 	}
-	columns = append(columns, table.Column{Title: "Amenities", Width: 20})
+	m.props = props
+	rows := m.mapPropertiesToRows(props)
 
 	t := table.New(
-		table.WithColumns(columns),
+		table.WithColumns(m.tableColumns()),
 		table.WithRows(rows),
 		table.WithFocused(true),
 		table.WithHeight(pageHeight+1),
 	)
 
-	s := table.DefaultStyles()
-	s.Header = s.Header.
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("240")).
-		BorderBottom(true).
-		Bold(false)
-	s.Selected = s.Selected.
-		Foreground(lipgloss.Color("229")).
-		Background(lipgloss.Color("57")).
-		Bold(false)
-	t.SetStyles(s)
-
-	m := model{table: t, currentPage: startPageNumber, maxPage: maxPage, pageHeight: pageHeight,
-		queryFilter: queryFilter, calcDistance: calcDistance, distX: distX, distY: distY}
-	if _, err := tea.NewProgram(m).Run(); err != nil {
+	t.SetStyles(tableStyles)
+
+	// The table only handles moving the selection, every other key is handled by the model
+	t.KeyMap = table.KeyMap{
+		LineUp:     keys.Up,
+		LineDown:   keys.Down,
+		GotoTop:    key.NewBinding(key.WithKeys("home")),
+		GotoBottom: key.NewBinding(key.WithKeys("end")),
+	}
+
+	m.table = t
+	if _, err := tea.NewProgram(m, tea.WithMouseCellMotion()).Run(); err != nil {
 		fmt.Println("Error displaying table:", err)
 		return
 	}
 }
 
-func mapPropertiesToRows(results []models.PropertyViewModel, calcDistance bool) []table.Row {
-	var rows []table.Row
+func getMaxPage(propsCount int, pageHeight int) int {
+	maxPage := propsCount / pageHeight
+	if propsCount%pageHeight > 0 {
+		maxPage++
+	}
 
-	for _, r := range results {
-		location := fmt.Sprintf("(%.2f,%.2f)", r.Latitude, r.Longitude)
-		price := fmt.Sprintf("$%.2f", r.Price)
-		sqft := fmt.Sprintf("%.2f", r.Square_footage)
-		rooms := fmt.Sprintf("%d", r.Rooms)
-		bathrooms := fmt.Sprintf("%d", r.Bathrooms)
-		distance := fmt.Sprintf("%.2f", r.Dist)
+	return maxPage
+}
 
-		row := table.Row{
-			r.Description, price, sqft, rooms, bathrooms, r.Lighting, location,
-		}
+func (m model) mapPropertiesToRows(results []models.PropertyViewModel) []table.Row {
+	var rows []table.Row
 
-		if calcDistance {
-			row = append(row, distance)
+	for _, r := range results {
+		mark := " "
+		if m.markedIndex(r.ID) != -1 {
+			mark = "*"
 		}
-		row = append(row, r.Amenities)
-
-		rows = append(rows, row)
+		rows = append(rows, append(table.Row{mark}, columns.Row(r, m.columns)...))
 	}
 
 	return rows
 }
-
-func getTableRows(queryFilter string, pageHeight int, pageNumber int, calcDistance bool, distX string, distY string) ([]table.Row, error) {
-	props, err := db.QueryProperties(queryFilter, pageHeight, (pageNumber-1)*pageHeight, calcDistance, distX, distY)
-	if err != nil {
-		return []table.Row{}, err
-	}
-	rows := mapPropertiesToRows(props, calcDistance)
-	return rows, nil
-}
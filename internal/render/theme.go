@@ -0,0 +1,132 @@
+/*
+Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
+*/
+package render
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const defaultTheme = "dark"
+
+type theme struct {
+	border             lipgloss.TerminalColor
+	selectedForeground lipgloss.TerminalColor
+	selectedBackground lipgloss.TerminalColor
+	highlight          lipgloss.TerminalColor
+	err                lipgloss.TerminalColor
+	// Highlights are shown with text attributes (reversed colors, underline) instead
+	noColor bool
+}
+
+var themes = map[string]theme{
+	"dark": {
+		border:             lipgloss.Color("240"),
+		selectedForeground: lipgloss.Color("229"),
+		selectedBackground: lipgloss.Color("57"),
+		highlight:          lipgloss.Color("42"),
+		err:                lipgloss.Color("9"),
+	},
+	"light": {
+		border:             lipgloss.Color("250"),
+		selectedForeground: lipgloss.Color("231"),
+		selectedBackground: lipgloss.Color("27"),
+		highlight:          lipgloss.Color("28"),
+		err:                lipgloss.Color("160"),
+	},
+	"high-contrast": {
+		border:             lipgloss.Color("15"),
+		selectedForeground: lipgloss.Color("0"),
+		selectedBackground: lipgloss.Color("11"),
+		highlight:          lipgloss.Color("10"),
+		err:                lipgloss.Color("9"),
+	},
+	"no-color": {
+		border:             lipgloss.NoColor{},
+		selectedForeground: lipgloss.NoColor{},
+		selectedBackground: lipgloss.NoColor{},
+		highlight:          lipgloss.NoColor{},
+		err:                lipgloss.NoColor{},
+		noColor:            true,
+	},
+}
+
+var (
+	baseStyle      lipgloss.Style
+	sectionStyle   lipgloss.Style
+	bestValueStyle lipgloss.Style
+	errorStyle     lipgloss.Style
+	tableStyles    table.Styles
+	helpStyles     help.Styles
+)
+
+func init() {
+	applyTheme(themes[defaultTheme])
+}
+
+// ThemeNames returns the names of the available themes, sorted.
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for n := range themes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// getTheme returns the theme with the given name, or the default one if it is empty. The
+// no-color theme is always used if the NO_COLOR environment variable is set.
+func getTheme(name string) (theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return themes["no-color"], nil
+	}
+	if name == "" {
+		name = defaultTheme
+	}
+
+	t, ok := themes[strings.ToLower(name)]
+	if !ok {
+		return theme{}, fmt.Errorf(`unknown theme "%s", available themes are: %s`, name, strings.Join(ThemeNames(), ", "))
+	}
+
+	return t, nil
+}
+
+// applyTheme sets the styles used to render every view.
+func applyTheme(t theme) {
+	baseStyle = lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(t.border)
+	sectionStyle = lipgloss.NewStyle().Bold(true).MarginTop(1)
+	bestValueStyle = lipgloss.NewStyle().Foreground(t.highlight).Bold(true).Underline(t.noColor)
+	errorStyle = lipgloss.NewStyle().Foreground(t.err)
+
+	tableStyles = table.DefaultStyles()
+	tableStyles.Header = tableStyles.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(t.border).
+		BorderBottom(true).
+		Bold(false)
+	tableStyles.Selected = tableStyles.Selected.
+		Foreground(t.selectedForeground).
+		Background(t.selectedBackground).
+		Reverse(t.noColor).
+		Bold(false)
+
+	helpStyles = help.New().Styles
+	if t.noColor {
+		helpStyles = help.Styles{
+			ShortKey: lipgloss.NewStyle(), ShortDesc: lipgloss.NewStyle(), ShortSeparator: lipgloss.NewStyle(),
+			Ellipsis: lipgloss.NewStyle(), FullKey: lipgloss.NewStyle(), FullDesc: lipgloss.NewStyle(),
+			FullSeparator: lipgloss.NewStyle(),
+		}
+	}
+}
@@ -4,20 +4,17 @@ Copyright © 2025 Santiago Tamashiro <santiago.tamashiro@gmail.com>
 package main
 
 import (
-	"fmt"
+	"context"
+	"os"
+	"os/signal"
 
 	"github.com/ta-ma/prop-filter-app/cmd"
-	"github.com/ta-ma/prop-filter-app/internal/config"
-	"github.com/ta-ma/prop-filter-app/internal/db"
 )
 
 func main() {
-	config, err := config.Read("config.json")
-	if err != nil {
-		fmt.Println("ERROR: Could not read JSON configuration file!")
-		panic(err)
-	}
+	// Ctrl+C cancels the queries in progress
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	db.Initialize(&config.DbConfig)
-	cmd.Execute(&config.Cli)
+	cmd.Execute(ctx)
 }
@@ -1,4 +1,5 @@
+: "${PROPFILTER_PG_PASSWORD:?Set PROPFILTER_PG_PASSWORD to the password of the database}"
 docker run  --rm -it --name filter-prop-db\
-	-e POSTGRES_USER=dbadmin -e POSTGRES_PASSWORD=filterpr0p \
+	-e POSTGRES_USER=dbadmin -e POSTGRES_PASSWORD="$PROPFILTER_PG_PASSWORD" \
 	-e POSTGRES_DB=filter-prop \
-	-p 8080:5432 postgres
\ No newline at end of file
+	-p 8080:5432 postgres